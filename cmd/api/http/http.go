@@ -1,13 +1,15 @@
 package http
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"regexp"
 
 	"github.com/connor-davis/dynamic-crud/cmd/api/http/routes"
 	"github.com/connor-davis/dynamic-crud/common"
+	"github.com/connor-davis/dynamic-crud/internal/auth"
 	"github.com/connor-davis/dynamic-crud/internal/routing"
-	"github.com/connor-davis/dynamic-crud/internal/routing/schemas"
 	"github.com/connor-davis/dynamic-crud/internal/storage"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gofiber/fiber/v2"
@@ -24,12 +26,31 @@ type httpRouter struct {
 }
 
 func NewHttpRouter(storage storage.Storage) HttpRouter {
-	usersRouter := routes.NewUsersRouter(storage)
+	authenticators := []routing.Authenticator{}
+
+	verifier, err := auth.NewVerifier(auth.ConfigFromEnv())
+
+	switch {
+	case err == nil:
+		authenticators = append(authenticators, auth.NewBearerAuthenticator(verifier))
+	case errors.Is(err, auth.ErrNotConfigured):
+		log.Printf("⚠️ JWT authentication is disabled: %v", err)
+	default:
+		panic("failed to initialize JWT authentication: " + err.Error())
+	}
+
+	authenticators = append(authenticators, auth.NewApiKeyAuthenticator(storage))
+
+	usersRouter := routes.NewUsersRouter(storage, authenticators)
 	usersRoutes := usersRouter.LoadRoutes()
 
+	apiKeysRouter := routes.NewApiKeysRouter(storage, authenticators)
+	apiKeysRoutes := apiKeysRouter.LoadRoutes()
+
 	routes := []routing.Route{}
 
 	routes = append(routes, usersRoutes...)
+	routes = append(routes, apiKeysRoutes...)
 
 	return &httpRouter{
 		storage: storage,
@@ -48,6 +69,8 @@ func (h *httpRouter) InitializeRoutes(router fiber.Router) {
 			router.Post(path, append(route.Middlewares, route.Handler)...)
 		case routing.PUT:
 			router.Put(path, append(route.Middlewares, route.Handler)...)
+		case routing.PATCH:
+			router.Patch(path, append(route.Middlewares, route.Handler)...)
 		case routing.DELETE:
 			router.Delete(path, append(route.Middlewares, route.Handler)...)
 		}
@@ -57,13 +80,19 @@ func (h *httpRouter) InitializeRoutes(router fiber.Router) {
 func (h *httpRouter) InitializeOpenAPI() *openapi3.T {
 	paths := openapi3.NewPaths()
 
-	schemas := openapi3.Schemas{
-		"SuccessResponse": schemas.SuccessSchema.NewRef(),
-		"ErrorResponse":   schemas.ErrorSchema.NewRef(),
-		"User":            schemas.UserSchema.NewRef(),
-	}
+	schemas := openapi3.Schemas{}
+
+	securitySchemes := openapi3.SecuritySchemes{}
 
 	for _, route := range h.routes {
+		for name, schema := range route.Schemas {
+			schemas[name] = schema
+		}
+
+		for name, securityScheme := range route.SecuritySchemes {
+			securitySchemes[name] = securityScheme
+		}
+
 		pathItem := &openapi3.PathItem{}
 
 		switch route.Method {
@@ -74,14 +103,9 @@ func (h *httpRouter) InitializeOpenAPI() *openapi3.T {
 				Tags:        route.Tags,
 				Parameters:  route.Parameters,
 				Responses:   route.Responses,
+				Security:    route.Security,
 			}
 		case routing.POST:
-			if route.CreateSchema == nil {
-				continue
-			}
-
-			schemas[fmt.Sprintf("Create%s", route.Entity)] = route.CreateSchema.NewRef()
-
 			pathItem.Post = &openapi3.Operation{
 				Summary:     route.Summary,
 				Description: route.Description,
@@ -89,14 +113,9 @@ func (h *httpRouter) InitializeOpenAPI() *openapi3.T {
 				Parameters:  route.Parameters,
 				RequestBody: route.RequestBody,
 				Responses:   route.Responses,
+				Security:    route.Security,
 			}
 		case routing.PUT:
-			if route.UpdateSchema == nil {
-				continue
-			}
-
-			schemas[fmt.Sprintf("Update%s", route.Entity)] = route.UpdateSchema.NewRef()
-
 			pathItem.Put = &openapi3.Operation{
 				Summary:     route.Summary,
 				Description: route.Description,
@@ -104,6 +123,17 @@ func (h *httpRouter) InitializeOpenAPI() *openapi3.T {
 				Parameters:  route.Parameters,
 				RequestBody: route.RequestBody,
 				Responses:   route.Responses,
+				Security:    route.Security,
+			}
+		case routing.PATCH:
+			pathItem.Patch = &openapi3.Operation{
+				Summary:     route.Summary,
+				Description: route.Description,
+				Tags:        route.Tags,
+				Parameters:  route.Parameters,
+				RequestBody: route.RequestBody,
+				Responses:   route.Responses,
+				Security:    route.Security,
 			}
 		case routing.DELETE:
 			pathItem.Delete = &openapi3.Operation{
@@ -111,7 +141,9 @@ func (h *httpRouter) InitializeOpenAPI() *openapi3.T {
 				Description: route.Description,
 				Tags:        route.Tags,
 				Parameters:  route.Parameters,
+				RequestBody: route.RequestBody,
 				Responses:   route.Responses,
+				Security:    route.Security,
 			}
 		}
 
@@ -127,6 +159,8 @@ func (h *httpRouter) InitializeOpenAPI() *openapi3.T {
 				existingPathItem.Post = pathItem.Post
 			case routing.PUT:
 				existingPathItem.Put = pathItem.Put
+			case routing.PATCH:
+				existingPathItem.Patch = pathItem.Patch
 			case routing.DELETE:
 				existingPathItem.Delete = pathItem.Delete
 			}
@@ -154,7 +188,8 @@ func (h *httpRouter) InitializeOpenAPI() *openapi3.T {
 		Tags:  openapi3.Tags{},
 		Paths: paths,
 		Components: &openapi3.Components{
-			Schemas: schemas,
+			Schemas:         schemas,
+			SecuritySchemes: securitySchemes,
 		},
 	}
 }
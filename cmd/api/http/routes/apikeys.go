@@ -0,0 +1,101 @@
+package routes
+
+import (
+	"context"
+	"slices"
+
+	"github.com/connor-davis/dynamic-crud/internal/auth"
+	"github.com/connor-davis/dynamic-crud/internal/crud"
+	"github.com/connor-davis/dynamic-crud/internal/models"
+	"github.com/connor-davis/dynamic-crud/internal/routing"
+	"github.com/connor-davis/dynamic-crud/internal/storage"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ApiKeysRouter struct {
+	storage        storage.Storage
+	authenticators []routing.Authenticator
+}
+
+func NewApiKeysRouter(storage storage.Storage, authenticators []routing.Authenticator) Router {
+	return &ApiKeysRouter{
+		storage:        storage,
+		authenticators: authenticators,
+	}
+}
+
+func (r *ApiKeysRouter) LoadRoutes() []routing.Route {
+	crudApi := crud.NewCrudApi[models.ApiKey](r.storage).
+		AssignSortableFields("name", "expiresAt", "lastUsedAt", "createdAt", "updatedAt").
+		AssignCacheControl("no-store", crud.OperationGetAll, crud.OperationGetOne, crud.OperationCreate, crud.OperationPatch).
+		BeforeCreate(issueApiKey)
+
+	for _, authenticator := range r.authenticators {
+		crudApi.AssignAuthentication(authenticator)
+	}
+
+	for _, operation := range []crud.Operation{
+		crud.OperationGetAll,
+		crud.OperationGetOne,
+		crud.OperationCreate,
+		crud.OperationPatch,
+		crud.OperationDelete,
+	} {
+		crudApi.Authorize(operation, adminOnly[models.ApiKey])
+	}
+
+	getAllRoute := crudApi.GetAllRoute()
+	getOneRoute := crudApi.GetOneRoute()
+	createRoute := withRepresentation(crudApi.CreateRoute())
+	patchRoute := crudApi.PatchRoute()
+	deleteRoute := crudApi.DeleteRoute()
+
+	return []routing.Route{
+		getAllRoute,
+		getOneRoute,
+		createRoute,
+		patchRoute,
+		deleteRoute,
+	}
+}
+
+func withRepresentation(route routing.Route) routing.Route {
+	handler := route.Handler
+
+	route.Parameters = slices.DeleteFunc(slices.Clone(route.Parameters), func(parameter *openapi3.ParameterRef) bool {
+		return parameter.Value != nil && parameter.Value.In == "header" && parameter.Value.Name == "Prefer"
+	})
+
+	route.Handler = func(ctx *fiber.Ctx) error {
+		ctx.Request().Header.Del("Prefer")
+
+		return handler(ctx)
+	}
+
+	return route
+}
+
+func issueApiKey(ctx context.Context, apiKey *models.ApiKey) error {
+	claims, _ := auth.ClaimsFrom(ctx)
+
+	if slices.Contains(apiKey.Scopes, crud.TenantSystemRole) && !claims.Grants(crud.TenantSystemRole) {
+		return crud.NewError(crud.KindForbidden, "Only system principals can issue API keys with the system scope.")
+	}
+
+	key, err := auth.GenerateApiKey()
+
+	if err != nil {
+		return err
+	}
+
+	apiKey.Key = key
+	apiKey.Prefix = key[:len(auth.ApiKeyPrefix)+8]
+	apiKey.Hash = auth.HashApiKey(key)
+
+	if apiKey.Scopes == nil {
+		apiKey.Scopes = []string{}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/connor-davis/dynamic-crud/internal/routing"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestWithRepresentationIgnoresMinimalPreference(t *testing.T) {
+	route := withRepresentation(routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Parameters: []*openapi3.ParameterRef{
+				{Value: openapi3.NewHeaderParameter("Prefer")},
+				{Value: openapi3.NewHeaderParameter("X-Tenant-ID")},
+			},
+		},
+		Handler: func(ctx *fiber.Ctx) error {
+			return ctx.SendString("prefer=" + ctx.Get("Prefer"))
+		},
+	})
+
+	if len(route.Parameters) != 1 || route.Parameters[0].Value.Name != "X-Tenant-ID" {
+		t.Fatalf("expected only the X-Tenant-ID parameter to remain, got %d parameters", len(route.Parameters))
+	}
+
+	app := fiber.New()
+	app.Post("/apikeys", route.Handler)
+
+	request := httptest.NewRequest(fiber.MethodPost, "/apikeys", nil)
+	request.Header.Set("Prefer", "return=minimal")
+
+	response, err := app.Test(request)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body, _ := io.ReadAll(response.Body)
+
+	if string(body) != "prefer=" {
+		t.Fatalf("expected the Prefer header to be dropped, got %q", body)
+	}
+}
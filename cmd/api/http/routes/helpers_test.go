@@ -0,0 +1,105 @@
+package routes
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/connor-davis/dynamic-crud/internal/auth"
+	"github.com/connor-davis/dynamic-crud/internal/crud"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type dryRunPool struct{}
+
+func (dryRunPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, nil
+}
+
+func (dryRunPool) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return nil, nil
+}
+
+func (dryRunPool) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return nil, nil
+}
+
+func (dryRunPool) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return nil
+}
+
+func (p dryRunPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return p, nil
+}
+
+func (dryRunPool) Commit() error {
+	return nil
+}
+
+func (dryRunPool) Rollback() error {
+	return nil
+}
+
+type dryRunStorage struct {
+	db *gorm.DB
+}
+
+func (s dryRunStorage) Database() *gorm.DB {
+	return s.db
+}
+
+func (s dryRunStorage) Migrate() error {
+	return nil
+}
+
+func newDryRunStorage(t *testing.T) dryRunStorage {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: dryRunPool{}}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		Logger:                 logger.Discard,
+	})
+
+	if err != nil {
+		t.Fatalf("failed to open dry run database: %v", err)
+	}
+
+	return dryRunStorage{db}
+}
+
+type claimsAuthenticator struct {
+	claims *auth.Claims
+}
+
+func (a claimsAuthenticator) Authenticate(ctx *fiber.Ctx) (bool, error) {
+	if a.claims == nil {
+		return false, nil
+	}
+
+	auth.SetClaims(ctx, a.claims)
+
+	return true, nil
+}
+
+func (a claimsAuthenticator) SecurityScheme() (string, *openapi3.SecurityScheme) {
+	return "TestAuth", openapi3.NewJWTSecurityScheme()
+}
+
+func newTestApp(router Router) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: crud.ErrorHandler})
+
+	for _, route := range router.LoadRoutes() {
+		path := regexp.MustCompile(`\{([^}]+)\}`).ReplaceAllString(route.Path, ":$1")
+
+		app.Add(string(route.Method), path, append(route.Middlewares, route.Handler)...)
+	}
+
+	return app
+}
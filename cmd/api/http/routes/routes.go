@@ -1,7 +1,32 @@
 package routes
 
-import "github.com/connor-davis/dynamic-crud/internal/routing"
+import (
+	"context"
+
+	"github.com/connor-davis/dynamic-crud/internal/auth"
+	"github.com/connor-davis/dynamic-crud/internal/crud"
+	"github.com/connor-davis/dynamic-crud/internal/routing"
+	"github.com/gofiber/fiber/v2"
+)
 
 type Router interface {
 	LoadRoutes() []routing.Route
 }
+
+func isAdmin(ctx *fiber.Ctx) bool {
+	claims, _ := auth.Locals(ctx)
+
+	return isAdministrator(claims)
+}
+
+func isAdministrator(claims *auth.Claims) bool {
+	return claims.Grants("admin")
+}
+
+func adminOnly[T any](ctx context.Context, claims *auth.Claims, entity *T) error {
+	if isAdministrator(claims) {
+		return nil
+	}
+
+	return crud.NewError(crud.KindForbidden, "Only administrators can perform this action.")
+}
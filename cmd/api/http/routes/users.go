@@ -1,39 +1,92 @@
 package routes
 
 import (
+	"context"
+	"time"
+
+	"github.com/connor-davis/dynamic-crud/internal/auth"
+	"github.com/connor-davis/dynamic-crud/internal/cache"
 	"github.com/connor-davis/dynamic-crud/internal/crud"
 	"github.com/connor-davis/dynamic-crud/internal/models"
 	"github.com/connor-davis/dynamic-crud/internal/routing"
-	"github.com/connor-davis/dynamic-crud/internal/routing/schemas"
 	"github.com/connor-davis/dynamic-crud/internal/storage"
 )
 
 type UsersRouter struct {
-	storage storage.Storage
+	storage        storage.Storage
+	authenticators []routing.Authenticator
 }
 
-func NewUsersRouter(storage storage.Storage) Router {
+func NewUsersRouter(storage storage.Storage, authenticators []routing.Authenticator) Router {
 	return &UsersRouter{
-		storage: storage,
+		storage:        storage,
+		authenticators: authenticators,
 	}
 }
 
 func (r *UsersRouter) LoadRoutes() []routing.Route {
 	crudApi := crud.NewCrudApi[models.User](r.storage).
-		AssignCreateSchema(schemas.CreateUserSchema).
-		AssignUpdateSchema(schemas.UpdateUserSchema)
+		AssignSortableFields("name", "email", "createdAt", "updatedAt").
+		AssignHardDeleteGuard(isAdmin).
+		AssignCacheControl("private, no-cache").
+		AssignCache(cache.NewLRU(1000, 30*time.Second))
+
+	for _, authenticator := range r.authenticators {
+		crudApi.AssignAuthentication(authenticator)
+	}
+
+	if len(r.authenticators) > 0 {
+		crudApi.
+			AssignOwner(isSelf).
+			AssignReadRoles("email", "admin", crud.OwnerRole).
+			Authorize(crud.OperationCreate, adminOnly[models.User]).
+			Authorize(crud.OperationUpdate, selfOrAdmin).
+			Authorize(crud.OperationPatch, selfOrAdmin).
+			Authorize(crud.OperationUpsert, selfOrAdmin).
+			Authorize(crud.OperationDelete, adminOnly[models.User]).
+			Authorize(crud.OperationRestore, adminOnly[models.User]).
+			Authorize(crud.OperationCacheStats, adminOnly[models.User])
+	}
 
 	getAllRoute := crudApi.GetAllRoute()
+	trashRoute := crudApi.TrashRoute()
 	getOneRoute := crudApi.GetOneRoute()
 	createRoute := crudApi.CreateRoute()
+	upsertRoute := crudApi.UpsertRoute()
+	bulkCreateRoute := crudApi.BulkCreateRoute()
+	bulkPatchRoute := crudApi.BulkPatchRoute()
+	bulkDeleteRoute := crudApi.BulkDeleteRoute()
 	updateRoute := crudApi.UpdateRoute()
+	patchRoute := crudApi.PatchRoute()
 	deleteRoute := crudApi.DeleteRoute()
+	restoreRoute := crudApi.RestoreRoute()
+	cacheStatsRoute := crudApi.CacheStatsRoute()
 
 	return []routing.Route{
 		getAllRoute,
+		trashRoute,
 		getOneRoute,
 		createRoute,
+		upsertRoute,
+		bulkCreateRoute,
+		bulkPatchRoute,
+		bulkDeleteRoute,
 		updateRoute,
+		patchRoute,
 		deleteRoute,
+		restoreRoute,
+		cacheStatsRoute,
 	}
 }
+
+func selfOrAdmin(ctx context.Context, claims *auth.Claims, user *models.User) error {
+	if isAdministrator(claims) || (claims != nil && isSelf(claims, user)) {
+		return nil
+	}
+
+	return crud.NewError(crud.KindForbidden, "You can only modify your own user.")
+}
+
+func isSelf(claims *auth.Claims, user *models.User) bool {
+	return claims.Subject == user.Id.String()
+}
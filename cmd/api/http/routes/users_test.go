@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/connor-davis/dynamic-crud/internal/auth"
+	"github.com/connor-davis/dynamic-crud/internal/routing"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestUserCreationRequiresAdministrator(t *testing.T) {
+	member := &auth.Claims{Subject: "u1", Raw: map[string]any{}}
+	admin := &auth.Claims{Subject: "u2", Raw: map[string]any{"roles": []any{"admin"}}}
+	adminKey := &auth.Claims{Subject: "apikey:1", Raw: map[string]any{"scopes": []any{"admin"}}}
+
+	requests := []struct {
+		name string
+		path string
+		body string
+	}{
+		{name: "create", path: "/users", body: `{"name":"Someone","email":"someone@example.com"}`},
+		{name: "bulk create", path: "/users/bulk", body: `{"items":[{"name":"Someone","email":"someone@example.com"}]}`},
+		{name: "upsert insert", path: "/users/upsert?on=email", body: `{"name":"Someone","email":"someone@example.com"}`},
+	}
+
+	principals := []struct {
+		name          string
+		claims        *auth.Claims
+		wantForbidden bool
+	}{
+		{name: "member", claims: member, wantForbidden: true},
+		{name: "admin role", claims: admin},
+		{name: "admin scope", claims: adminKey},
+	}
+
+	for _, request := range requests {
+		for _, principal := range principals {
+			t.Run(request.name+" as "+principal.name, func(t *testing.T) {
+				router := NewUsersRouter(newDryRunStorage(t), []routing.Authenticator{claimsAuthenticator{principal.claims}})
+
+				httpRequest := httptest.NewRequest(fiber.MethodPost, request.path, strings.NewReader(request.body))
+				httpRequest.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+				response, err := newTestApp(router).Test(httpRequest)
+
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				body, _ := io.ReadAll(response.Body)
+
+				if forbidden := response.StatusCode == fiber.StatusForbidden; forbidden != principal.wantForbidden {
+					t.Fatalf("expected forbidden %v, got status %d: %s", principal.wantForbidden, response.StatusCode, body)
+				}
+			})
+		}
+	}
+}
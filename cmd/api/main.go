@@ -7,6 +7,7 @@ import (
 	"github.com/MarceloPetrucio/go-scalar-api-reference"
 	"github.com/connor-davis/dynamic-crud/cmd/api/http"
 	"github.com/connor-davis/dynamic-crud/common"
+	"github.com/connor-davis/dynamic-crud/internal/crud"
 	"github.com/connor-davis/dynamic-crud/internal/storage"
 	"github.com/goccy/go-json"
 	"github.com/gofiber/fiber/v2"
@@ -23,6 +24,7 @@ func main() {
 		ServerHeader: common.EnvString("APP_HEADER", "Dynamic-CRUD"),
 		JSONEncoder:  json.Marshal,
 		JSONDecoder:  json.Unmarshal,
+		ErrorHandler: crud.ErrorHandler,
 	})
 
 	app.Use(cors.New(cors.Config{
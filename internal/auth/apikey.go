@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/connor-davis/dynamic-crud/internal/models"
+	"github.com/connor-davis/dynamic-crud/internal/routing"
+	"github.com/connor-davis/dynamic-crud/internal/storage"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ApiKeyScheme = "ApiKeyAuth"
+	ApiKeyHeader = "X-API-Key"
+	ApiKeyPrefix = "dc_"
+)
+
+const (
+	lastUsedInterval = time.Minute
+	lastUsedTimeout  = 5 * time.Second
+)
+
+type apiKeyAuthenticator struct {
+	storage storage.Storage
+}
+
+func NewApiKeyAuthenticator(storage storage.Storage) routing.Authenticator {
+	return &apiKeyAuthenticator{
+		storage: storage,
+	}
+}
+
+func GenerateApiKey() (string, error) {
+	secret := make([]byte, 32)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return ApiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+func HashApiKey(key string) string {
+	digest := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(digest[:])
+}
+
+func (a *apiKeyAuthenticator) Authenticate(ctx *fiber.Ctx) (bool, error) {
+	key := ctx.Get(ApiKeyHeader)
+
+	if key == "" {
+		return false, nil
+	}
+
+	var apiKey models.ApiKey
+
+	err := a.storage.Database().
+		WithContext(ctx.UserContext()).
+		Where("hash = ?", HashApiKey(key)).
+		First(&apiKey).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, fiber.NewError(fiber.StatusUnauthorized, "The API key is invalid.")
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+
+	if apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt) {
+		return false, fiber.NewError(fiber.StatusUnauthorized, "The API key has expired.")
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > lastUsedInterval {
+		go a.touch(context.WithoutCancel(ctx.UserContext()), apiKey.Id, now)
+	}
+
+	subject := "apikey:" + apiKey.Id.String()
+	scopes := []any{}
+
+	for _, scope := range apiKey.Scopes {
+		scopes = append(scopes, scope)
+	}
+
+	raw := map[string]any{
+		"sub":    subject,
+		"scopes": scopes,
+	}
+
+	if apiKey.OwnerTenantId != nil {
+		raw["tenant_id"] = apiKey.OwnerTenantId.String()
+	}
+
+	SetClaims(ctx, &Claims{
+		Subject:   subject,
+		ExpiresAt: timeOrZero(apiKey.ExpiresAt),
+		Raw:       raw,
+	})
+
+	return true, nil
+}
+
+func (a *apiKeyAuthenticator) touch(ctx context.Context, apiKeyId uuid.UUID, usedAt time.Time) {
+	ctx, cancel := context.WithTimeout(ctx, lastUsedTimeout)
+	defer cancel()
+
+	if err := a.storage.Database().
+		WithContext(ctx).
+		Model(&models.ApiKey{}).
+		Where("id = ?", apiKeyId).
+		UpdateColumn("last_used_at", usedAt).Error; err != nil {
+		log.Printf("⚠️ Failed to record the last use of API key %s: %v", apiKeyId, err)
+	}
+}
+
+func (a *apiKeyAuthenticator) SecurityScheme() (string, *openapi3.SecurityScheme) {
+	return ApiKeyScheme, openapi3.NewSecurityScheme().
+		WithType("apiKey").
+		WithIn("header").
+		WithName(ApiKeyHeader).
+		WithDescription("An API key issued by an administrator. Keys are stored as hashes and are only shown once when issued.")
+}
+
+func timeOrZero(value *time.Time) time.Time {
+	if value == nil {
+		return time.Time{}
+	}
+
+	return *value
+}
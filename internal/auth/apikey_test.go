@@ -0,0 +1,344 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/connor-davis/dynamic-crud/internal/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type keyStore struct {
+	mutex   sync.Mutex
+	keys    []models.ApiKey
+	gate    chan struct{}
+	failure error
+	touched chan uuid.UUID
+}
+
+func (s *keyStore) Connect(ctx context.Context) (driver.Conn, error) {
+	return &keyConn{store: s}, nil
+}
+
+func (s *keyStore) Driver() driver.Driver {
+	return nil
+}
+
+type keyConn struct {
+	store *keyStore
+}
+
+func (c *keyConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *keyConn) Close() error {
+	return nil
+}
+
+func (c *keyConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (c *keyConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.store.mutex.Lock()
+	defer c.store.mutex.Unlock()
+
+	rows := &keyRows{}
+
+	for _, key := range c.store.keys {
+		if key.Hash != args[0].Value {
+			continue
+		}
+
+		if key.DeletedAt.Valid && strings.Contains(query, `"deleted_at" IS NULL`) {
+			continue
+		}
+
+		rows.keys = append(rows.keys, key)
+	}
+
+	return rows, nil
+}
+
+func (c *keyConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.store.mutex.Lock()
+	gate, failure := c.store.gate, c.store.failure
+	c.store.mutex.Unlock()
+
+	if gate != nil {
+		select {
+		case <-gate:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if failure != nil {
+		return nil, failure
+	}
+
+	for _, arg := range args {
+		if value, ok := arg.Value.(string); ok {
+			if id, err := uuid.Parse(value); err == nil {
+				c.store.touched <- id
+			}
+		}
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+type keyRows struct {
+	keys []models.ApiKey
+}
+
+func (r *keyRows) Columns() []string {
+	return []string{"id", "created_at", "updated_at", "deleted_at", "name", "prefix", "hash", "scopes", "expires_at", "last_used_at", "owner_tenant_id"}
+}
+
+func (r *keyRows) Close() error {
+	return nil
+}
+
+func (r *keyRows) Next(dest []driver.Value) error {
+	if len(r.keys) == 0 {
+		return io.EOF
+	}
+
+	key := r.keys[0]
+	r.keys = r.keys[1:]
+
+	scopes, _ := json.Marshal(key.Scopes)
+
+	dest[0] = key.Id.String()
+	dest[1] = key.CreatedAt
+	dest[2] = key.UpdatedAt
+	dest[3] = nullable(key.DeletedAt.Valid, key.DeletedAt.Time)
+	dest[4] = key.Name
+	dest[5] = key.Prefix
+	dest[6] = key.Hash
+	dest[7] = scopes
+	dest[8] = nil
+	dest[9] = nil
+	dest[10] = nil
+
+	if key.ExpiresAt != nil {
+		dest[8] = *key.ExpiresAt
+	}
+
+	if key.LastUsedAt != nil {
+		dest[9] = *key.LastUsedAt
+	}
+
+	if key.OwnerTenantId != nil {
+		dest[10] = key.OwnerTenantId.String()
+	}
+
+	return nil
+}
+
+func nullable(valid bool, value time.Time) driver.Value {
+	if !valid {
+		return nil
+	}
+
+	return value
+}
+
+type keyStorage struct {
+	db *gorm.DB
+}
+
+func (s keyStorage) Database() *gorm.DB {
+	return s.db
+}
+
+func (s keyStorage) Migrate() error {
+	return nil
+}
+
+func newKeyStorage(t *testing.T, store *keyStore) keyStorage {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(store)}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		Logger:                 logger.Discard,
+	})
+
+	if err != nil {
+		t.Fatalf("failed to open the key store: %v", err)
+	}
+
+	return keyStorage{db}
+}
+
+func TestApiKeyAuthenticator(t *testing.T) {
+	now := time.Now()
+	expired := now.Add(-time.Hour)
+	later := now.Add(time.Hour)
+	recently := now.Add(-time.Second)
+	tenant := uuid.New()
+
+	issue := func(key models.ApiKey) (string, models.ApiKey) {
+		raw, err := GenerateApiKey()
+
+		if err != nil {
+			t.Fatalf("failed to generate an API key: %v", err)
+		}
+
+		key.Id = uuid.New()
+		key.Name = "test key"
+		key.Prefix = raw[:len(ApiKeyPrefix)+8]
+		key.Hash = HashApiKey(raw)
+
+		if key.Scopes == nil {
+			key.Scopes = []string{}
+		}
+
+		return raw, key
+	}
+
+	validRaw, valid := issue(models.ApiKey{Scopes: []string{"read", "admin"}, ExpiresAt: &later})
+	tenantRaw, tenantKey := issue(models.ApiKey{OwnerTenantId: &tenant})
+	recentRaw, recentKey := issue(models.ApiKey{LastUsedAt: &recently})
+	expiredRaw, expiredKey := issue(models.ApiKey{ExpiresAt: &expired})
+	revokedRaw, revokedKey := issue(models.ApiKey{})
+	revokedKey.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+
+	keys := []models.ApiKey{valid, tenantKey, recentKey, expiredKey, revokedKey}
+
+	tests := []struct {
+		name        string
+		key         string
+		gate        bool
+		failure     error
+		wantStatus  int
+		wantBody    string
+		wantClaims  map[string]any
+		wantTouched bool
+	}{
+		{name: "no key", wantStatus: fiber.StatusUnauthorized, wantBody: "Authentication is required to access this resource."},
+		{name: "unknown hash", key: ApiKeyPrefix + "unknown", wantStatus: fiber.StatusUnauthorized, wantBody: "The API key is invalid."},
+		{name: "expired", key: expiredRaw, wantStatus: fiber.StatusUnauthorized, wantBody: "The API key has expired."},
+		{name: "revoked", key: revokedRaw, wantStatus: fiber.StatusUnauthorized, wantBody: "The API key is invalid."},
+		{
+			name:        "valid",
+			key:         validRaw,
+			wantStatus:  fiber.StatusOK,
+			wantClaims:  map[string]any{"sub": "apikey:" + valid.Id.String(), "scopes": []any{"read", "admin"}},
+			wantTouched: true,
+		},
+		{
+			name:        "tenant",
+			key:         tenantRaw,
+			wantStatus:  fiber.StatusOK,
+			wantClaims:  map[string]any{"sub": "apikey:" + tenantKey.Id.String(), "scopes": []any{}, "tenant_id": tenant.String()},
+			wantTouched: true,
+		},
+		{
+			name:       "recently used",
+			key:        recentRaw,
+			wantStatus: fiber.StatusOK,
+			wantClaims: map[string]any{"sub": "apikey:" + recentKey.Id.String(), "scopes": []any{}},
+		},
+		{
+			name:        "slow last used update",
+			key:         validRaw,
+			gate:        true,
+			wantStatus:  fiber.StatusOK,
+			wantClaims:  map[string]any{"sub": "apikey:" + valid.Id.String(), "scopes": []any{"read", "admin"}},
+			wantTouched: true,
+		},
+		{
+			name:       "failed last used update",
+			key:        validRaw,
+			failure:    errors.New("connection reset"),
+			wantStatus: fiber.StatusOK,
+			wantClaims: map[string]any{"sub": "apikey:" + valid.Id.String(), "scopes": []any{"read", "admin"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := &keyStore{keys: keys, failure: test.failure, touched: make(chan uuid.UUID, 1)}
+
+			if test.gate {
+				store.gate = make(chan struct{})
+			}
+
+			app := fiber.New()
+			app.Get("/", Required(NewApiKeyAuthenticator(newKeyStorage(t, store))), func(ctx *fiber.Ctx) error {
+				claims, _ := Locals(ctx)
+
+				return ctx.JSON(claims.Raw)
+			})
+
+			request := httptest.NewRequest(fiber.MethodGet, "/", nil)
+
+			if test.key != "" {
+				request.Header.Set(ApiKeyHeader, test.key)
+			}
+
+			response, err := app.Test(request, 1000)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			body, _ := io.ReadAll(response.Body)
+
+			if response.StatusCode != test.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", test.wantStatus, response.StatusCode, body)
+			}
+
+			if test.wantBody != "" && string(body) != test.wantBody {
+				t.Fatalf("expected body %q, got %q", test.wantBody, body)
+			}
+
+			if test.wantClaims != nil {
+				claims := map[string]any{}
+
+				if err := json.Unmarshal(body, &claims); err != nil {
+					t.Fatalf("failed to decode claims: %v", err)
+				}
+
+				want, _ := json.Marshal(test.wantClaims)
+				got, _ := json.Marshal(claims)
+
+				if string(want) != string(got) {
+					t.Fatalf("expected claims %s, got %s", want, got)
+				}
+			}
+
+			if store.gate != nil {
+				close(store.gate)
+			}
+
+			select {
+			case id := <-store.touched:
+				if !test.wantTouched {
+					t.Fatalf("expected no last used update, got one for %s", id)
+				}
+			case <-time.After(200 * time.Millisecond):
+				if test.wantTouched {
+					t.Fatal("expected the last used time to be recorded")
+				}
+			}
+		})
+	}
+}
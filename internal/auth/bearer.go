@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"log"
+	"strings"
+
+	"github.com/connor-davis/dynamic-crud/internal/routing"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+)
+
+const BearerScheme = "BearerAuth"
+
+type bearerAuthenticator struct {
+	verifier Verifier
+}
+
+func NewBearerAuthenticator(verifier Verifier) routing.Authenticator {
+	return &bearerAuthenticator{
+		verifier: verifier,
+	}
+}
+
+func (b *bearerAuthenticator) Authenticate(ctx *fiber.Ctx) (bool, error) {
+	scheme, token, found := strings.Cut(ctx.Get(fiber.HeaderAuthorization), " ")
+
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return false, nil
+	}
+
+	claims, err := b.verifier.Verify(ctx.UserContext(), strings.TrimSpace(token))
+
+	if err != nil {
+		ctx.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+
+		log.Printf("⚠️ Rejected a bearer token for %s %s: %v", ctx.Method(), ctx.Path(), err)
+
+		return false, fiber.NewError(fiber.StatusUnauthorized, "The access token is invalid.")
+	}
+
+	SetClaims(ctx, claims)
+
+	return true, nil
+}
+
+func (b *bearerAuthenticator) SecurityScheme() (string, *openapi3.SecurityScheme) {
+	return BearerScheme, openapi3.NewJWTSecurityScheme()
+}
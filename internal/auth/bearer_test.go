@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestBearerAuthenticatorHidesVerificationErrors(t *testing.T) {
+	secret := []byte("secret")
+
+	verifier, err := NewVerifier(Config{Secret: secret, Audience: "api"})
+
+	if err != nil {
+		t.Fatalf("failed to create the verifier: %v", err)
+	}
+
+	app := fiber.New()
+	app.Get("/", Required(NewBearerAuthenticator(verifier)), func(ctx *fiber.Ctx) error {
+		claims, _ := Locals(ctx)
+
+		return ctx.SendString(claims.Subject)
+	})
+
+	header := map[string]any{"alg": "HS256", "typ": "JWT"}
+	valid := map[string]any{"sub": "u1", "aud": "api", "exp": time.Now().Add(time.Hour).Unix()}
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "valid", token: signHS256(t, secret, header, valid), wantStatus: fiber.StatusOK, wantBody: "u1"},
+		{name: "wrong audience", token: signHS256(t, secret, header, map[string]any{"sub": "u1", "aud": "other", "exp": valid["exp"]}), wantStatus: fiber.StatusUnauthorized, wantBody: "The access token is invalid."},
+		{name: "bad signature", token: signHS256(t, []byte("guess"), header, valid), wantStatus: fiber.StatusUnauthorized, wantBody: "The access token is invalid."},
+		{name: "alg none", token: encodeSegment(t, map[string]any{"alg": "none"}) + "." + encodeSegment(t, valid) + ".", wantStatus: fiber.StatusUnauthorized, wantBody: "The access token is invalid."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			request.Header.Set(fiber.HeaderAuthorization, "Bearer "+test.token)
+
+			response, err := app.Test(request)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			body, _ := io.ReadAll(response.Body)
+
+			if response.StatusCode != test.wantStatus || string(body) != test.wantBody {
+				t.Fatalf("expected %d %q, got %d %q", test.wantStatus, test.wantBody, response.StatusCode, body)
+			}
+
+			if test.wantStatus == fiber.StatusUnauthorized && !strings.Contains(response.Header.Get(fiber.HeaderWWWAuthenticate), "invalid_token") {
+				t.Fatalf("expected an invalid_token challenge, got %q", response.Header.Get(fiber.HeaderWWWAuthenticate))
+			}
+		})
+	}
+}
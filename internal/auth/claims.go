@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const claimsLocal = "claims"
+
+type Claims struct {
+	Subject   string
+	Issuer    string
+	Audience  []string
+	ExpiresAt time.Time
+	NotBefore time.Time
+	IssuedAt  time.Time
+	Raw       map[string]any
+}
+
+type claimsKey struct{}
+
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+func ClaimsFrom(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+
+	return claims, ok
+}
+
+func Locals(ctx *fiber.Ctx) (*Claims, bool) {
+	claims, ok := ctx.Locals(claimsLocal).(*Claims)
+
+	return claims, ok
+}
+
+func SetClaims(ctx *fiber.Ctx, claims *Claims) {
+	ctx.Locals(claimsLocal, claims)
+	ctx.SetUserContext(WithClaims(ctx.UserContext(), claims))
+}
+
+func (c *Claims) String(name string) string {
+	value, _ := c.Raw[name].(string)
+
+	return value
+}
+
+func (c *Claims) Strings(name string) []string {
+	switch value := c.Raw[name].(type) {
+	case string:
+		return []string{value}
+	case []any:
+		values := []string{}
+
+		for _, item := range value {
+			if item, ok := item.(string); ok {
+				values = append(values, item)
+			}
+		}
+
+		return values
+	}
+
+	return nil
+}
+
+func (c *Claims) Roles() []string {
+	if c == nil {
+		return nil
+	}
+
+	return c.Strings("roles")
+}
+
+func (c *Claims) HasRole(role string) bool {
+	return slices.Contains(c.Roles(), role)
+}
+
+func (c *Claims) Scopes() []string {
+	if c == nil {
+		return nil
+	}
+
+	if scope := c.String("scope"); scope != "" {
+		return strings.Fields(scope)
+	}
+
+	return c.Strings("scopes")
+}
+
+func (c *Claims) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes(), scope)
+}
+
+func (c *Claims) Grants(permission string) bool {
+	return c.HasRole(permission) || c.HasScope(permission)
+}
+
+func (c *Claims) HasAudience(audience string) bool {
+	return slices.Contains(c.Audience, audience)
+}
+
+func parseClaims(payload []byte) (*Claims, error) {
+	raw := map[string]any{}
+
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("the token claims are not a JSON object: %w", err)
+	}
+
+	claims := &Claims{
+		Raw: raw,
+	}
+
+	claims.Subject = claims.String("sub")
+	claims.Issuer = claims.String("iss")
+	claims.Audience = claims.Strings("aud")
+
+	for name, target := range map[string]*time.Time{"exp": &claims.ExpiresAt, "nbf": &claims.NotBefore, "iat": &claims.IssuedAt} {
+		value, exists := raw[name]
+
+		if !exists {
+			continue
+		}
+
+		seconds, ok := value.(float64)
+
+		if !ok {
+			return nil, fmt.Errorf("the %s claim must be a number", name)
+		}
+
+		*target = time.Unix(int64(seconds), 0)
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func encodeSegment(t *testing.T, value any) string {
+	t.Helper()
+
+	data, err := json.Marshal(value)
+
+	if err != nil {
+		t.Fatalf("failed to encode token segment: %v", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signHS256(t *testing.T, secret []byte, header map[string]any, claims map[string]any) string {
+	t.Helper()
+
+	signed := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, header map[string]any, claims map[string]any) string {
+	t.Helper()
+
+	signed := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+
+	return key
+}
+
+func jwksDocument(keys map[string]*rsa.PrivateKey) map[string]any {
+	entries := []map[string]any{}
+
+	for kid, key := range keys {
+		entries = append(entries, map[string]any{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+
+	return map[string]any{"keys": entries}
+}
@@ -0,0 +1,228 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type keySet struct {
+	mutex     sync.RWMutex
+	group     singleflight.Group
+	source    string
+	refresh   time.Duration
+	client    *http.Client
+	keys      map[string]*rsa.PublicKey
+	loadedAt  time.Time
+	attempted time.Time
+}
+
+const minimumRefresh = time.Minute
+
+func newKeySet(source string, refresh time.Duration) (*keySet, error) {
+	set := &keySet{
+		source:  source,
+		refresh: refresh,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+
+	keys, err := set.fetch(context.Background())
+
+	if err != nil {
+		return nil, err
+	}
+
+	set.store(keys)
+
+	return set, nil
+}
+
+func (s *keySet) remote() bool {
+	return strings.HasPrefix(s.source, "https://") || strings.HasPrefix(s.source, "http://")
+}
+
+func (s *keySet) key(ctx context.Context, kid string) ([]*rsa.PublicKey, error) {
+	if s.remote() && s.outdated(kid) {
+		_, err, _ := s.group.Do(kid, func() (any, error) {
+			return nil, s.reload(context.WithoutCancel(ctx))
+		})
+
+		if err != nil && s.empty() {
+			return nil, err
+		}
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if kid != "" {
+		key, exists := s.keys[kid]
+
+		if !exists {
+			return nil, fmt.Errorf("the token was signed with an unknown key %q", kid)
+		}
+
+		return []*rsa.PublicKey{key}, nil
+	}
+
+	keys := []*rsa.PublicKey{}
+
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (s *keySet) outdated(kid string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	_, known := s.keys[kid]
+	stale := s.refresh > 0 && time.Since(s.loadedAt) > s.refresh
+
+	return stale || (kid != "" && !known)
+}
+
+func (s *keySet) empty() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.keys) == 0
+}
+
+func (s *keySet) reload(ctx context.Context) error {
+	s.mutex.Lock()
+
+	if time.Since(s.attempted) <= minimumRefresh {
+		s.mutex.Unlock()
+
+		return nil
+	}
+
+	s.attempted = time.Now()
+	s.mutex.Unlock()
+
+	keys, err := s.fetch(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	s.store(keys)
+
+	return nil
+}
+
+func (s *keySet) store(keys map[string]*rsa.PublicKey) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.keys = keys
+	s.loadedAt = time.Now()
+}
+
+func (s *keySet) fetch(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	data, err := s.read(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWKS from %s: %w", s.source, err)
+	}
+
+	document := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS from %s: %w", s.source, err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+
+	for _, key := range document.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") || (key.Alg != "" && key.Alg != "RS256") {
+			continue
+		}
+
+		publicKey, err := key.rsa()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWKS key %q: %w", key.Kid, err)
+		}
+
+		keys[key.Kid] = publicKey
+	}
+
+	return keys, nil
+}
+
+func (s *keySet) read(ctx context.Context) ([]byte, error) {
+	if !s.remote() {
+		return os.ReadFile(s.source)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.client.Do(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(response.Body, 1<<20))
+}
+
+func (k jwk) rsa() (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(k.N)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+
+	exponent, err := base64.RawURLEncoding.DecodeString(k.E)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+
+	e := new(big.Int).SetBytes(exponent)
+
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("invalid exponent")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(modulus),
+		E: int(e.Int64()),
+	}, nil
+}
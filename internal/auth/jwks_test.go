@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type jwksServer struct {
+	*httptest.Server
+	mutex   sync.Mutex
+	keys    map[string]*rsa.PrivateKey
+	fetches atomic.Int32
+	gate    chan struct{}
+}
+
+func newJWKSServer(t *testing.T, keys map[string]*rsa.PrivateKey) *jwksServer {
+	t.Helper()
+
+	server := &jwksServer{keys: keys}
+
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.fetches.Add(1)
+
+		server.mutex.Lock()
+		gate := server.gate
+		document := jwksDocument(server.keys)
+		server.mutex.Unlock()
+
+		if gate != nil {
+			<-gate
+		}
+
+		json.NewEncoder(w).Encode(document)
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func (s *jwksServer) rotate(keys map[string]*rsa.PrivateKey, gate chan struct{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.keys = keys
+	s.gate = gate
+}
+
+func TestKeySetRefreshesOnUnknownKid(t *testing.T) {
+	primary := newRSAKey(t)
+	rotated := newRSAKey(t)
+
+	server := newJWKSServer(t, map[string]*rsa.PrivateKey{"primary": primary})
+
+	keys, err := newKeySet(server.URL, time.Hour)
+
+	if err != nil {
+		t.Fatalf("failed to load JWKS: %v", err)
+	}
+
+	server.rotate(map[string]*rsa.PrivateKey{"primary": primary, "rotated": rotated}, nil)
+
+	var wait sync.WaitGroup
+
+	for range 10 {
+		wait.Add(1)
+
+		go func() {
+			defer wait.Done()
+
+			found, err := keys.key(context.Background(), "rotated")
+
+			if err != nil || len(found) != 1 || found[0].N.Cmp(rotated.N) != 0 {
+				t.Errorf("expected the rotated key, got %v %v", found, err)
+			}
+		}()
+	}
+
+	wait.Wait()
+
+	if fetches := server.fetches.Load(); fetches != 2 {
+		t.Fatalf("expected one refresh after the initial load, got %d fetches", fetches)
+	}
+
+	if _, err := keys.key(context.Background(), "forged"); err == nil {
+		t.Fatal("expected an unknown kid to be rejected")
+	}
+
+	if fetches := server.fetches.Load(); fetches != 2 {
+		t.Fatalf("expected unknown kids not to refresh again within %s, got %d fetches", minimumRefresh, fetches)
+	}
+}
+
+func TestKeySetServesKnownKeysDuringRefresh(t *testing.T) {
+	primary := newRSAKey(t)
+
+	server := newJWKSServer(t, map[string]*rsa.PrivateKey{"primary": primary})
+
+	keys, err := newKeySet(server.URL, time.Hour)
+
+	if err != nil {
+		t.Fatalf("failed to load JWKS: %v", err)
+	}
+
+	gate := make(chan struct{})
+	server.rotate(map[string]*rsa.PrivateKey{"primary": primary}, gate)
+
+	refreshed := make(chan error, 1)
+
+	go func() {
+		_, err := keys.key(context.Background(), "rotated")
+
+		refreshed <- err
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for server.fetches.Load() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("the refresh never started")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	looked := make(chan error, 1)
+
+	go func() {
+		_, err := keys.key(context.Background(), "primary")
+
+		looked <- err
+	}()
+
+	select {
+	case err := <-looked:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("a known key was blocked by the refresh")
+	}
+
+	close(gate)
+
+	if err := <-refreshed; err == nil {
+		t.Fatal("expected the unknown kid to be rejected after the refresh")
+	}
+}
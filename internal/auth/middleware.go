@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"github.com/connor-davis/dynamic-crud/internal/routing"
+	"github.com/gofiber/fiber/v2"
+)
+
+func Required(authenticators ...routing.Authenticator) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		for _, authenticator := range authenticators {
+			authenticated, err := authenticator.Authenticate(ctx)
+
+			if err != nil {
+				return err
+			}
+
+			if authenticated {
+				return ctx.Next()
+			}
+		}
+
+		for _, authenticator := range authenticators {
+			if scheme, _ := authenticator.SecurityScheme(); scheme == BearerScheme {
+				ctx.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			}
+		}
+
+		return fiber.NewError(fiber.StatusUnauthorized, "Authentication is required to access this resource.")
+	}
+}
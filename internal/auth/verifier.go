@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/connor-davis/dynamic-crud/common"
+)
+
+var ErrNotConfigured = errors.New("neither a JWT secret nor a JWKS source is configured")
+
+type Config struct {
+	Secret      []byte
+	JWKS        string
+	JWKSRefresh time.Duration
+	Issuer      string
+	Audience    string
+	ClockSkew   time.Duration
+}
+
+type Verifier interface {
+	Verify(ctx context.Context, token string) (*Claims, error)
+}
+
+type verifier struct {
+	config Config
+	keys   *keySet
+}
+
+func ConfigFromEnv() Config {
+	return Config{
+		Secret:      []byte(common.EnvString("APP_JWT_SECRET", "")),
+		JWKS:        common.EnvString("APP_JWT_JWKS", ""),
+		JWKSRefresh: parseDuration(common.EnvString("APP_JWT_JWKS_REFRESH", "1h")),
+		Issuer:      common.EnvString("APP_JWT_ISSUER", ""),
+		Audience:    common.EnvString("APP_JWT_AUDIENCE", ""),
+		ClockSkew:   parseDuration(common.EnvString("APP_JWT_CLOCK_SKEW", "30s")),
+	}
+}
+
+func NewVerifier(config Config) (Verifier, error) {
+	if len(config.Secret) == 0 && config.JWKS == "" {
+		return nil, ErrNotConfigured
+	}
+
+	v := &verifier{
+		config: config,
+	}
+
+	if config.JWKS != "" {
+		keys, err := newKeySet(config.JWKS, config.JWKSRefresh)
+
+		if err != nil {
+			return nil, err
+		}
+
+		v.keys = keys
+	}
+
+	return v, nil
+}
+
+func (v *verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("the token is not a JWT")
+	}
+
+	headerData, err := base64.RawURLEncoding.DecodeString(parts[0])
+
+	if err != nil {
+		return nil, fmt.Errorf("the token header is not base64url encoded")
+	}
+
+	header := struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{}
+
+	if err := json.Unmarshal(headerData, &header); err != nil {
+		return nil, fmt.Errorf("the token header is not a JSON object")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+
+	if err != nil {
+		return nil, fmt.Errorf("the token signature is not base64url encoded")
+	}
+
+	if err := v.verifySignature(ctx, header.Alg, header.Kid, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+
+	if err != nil {
+		return nil, fmt.Errorf("the token payload is not base64url encoded")
+	}
+
+	claims, err := parseClaims(payload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.validate(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (v *verifier) verifySignature(ctx context.Context, algorithm string, kid string, signed string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signed))
+
+	switch {
+	case algorithm == "HS256" && len(v.config.Secret) > 0:
+		mac := hmac.New(sha256.New, v.config.Secret)
+		mac.Write([]byte(signed))
+
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return fmt.Errorf("the token signature is invalid")
+		}
+
+		return nil
+	case algorithm == "RS256" && v.keys != nil:
+		keys, err := v.keys.key(ctx, kid)
+
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("the token signature is invalid")
+	}
+
+	return fmt.Errorf("the token algorithm %q is not accepted", algorithm)
+}
+
+func (v *verifier) validate(claims *Claims) error {
+	now := time.Now()
+
+	if claims.ExpiresAt.IsZero() {
+		return fmt.Errorf("the token has no expiry")
+	}
+
+	if now.After(claims.ExpiresAt.Add(v.config.ClockSkew)) {
+		return fmt.Errorf("the token has expired")
+	}
+
+	if !claims.NotBefore.IsZero() && now.Add(v.config.ClockSkew).Before(claims.NotBefore) {
+		return fmt.Errorf("the token is not valid yet")
+	}
+
+	if v.config.Issuer != "" && claims.Issuer != v.config.Issuer {
+		return fmt.Errorf("the token was issued by %q", claims.Issuer)
+	}
+
+	if v.config.Audience != "" && !claims.HasAudience(v.config.Audience) {
+		return fmt.Errorf("the token is not intended for this audience")
+	}
+
+	return nil
+}
+
+func parseDuration(value string) time.Duration {
+	duration, err := time.ParseDuration(value)
+
+	if err != nil || duration < 0 {
+		return 0
+	}
+
+	return duration
+}
@@ -0,0 +1,254 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeJWKS(t *testing.T, document map[string]any) string {
+	t.Helper()
+
+	data, err := json.Marshal(document)
+
+	if err != nil {
+		t.Fatalf("failed to encode JWKS: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write JWKS: %v", err)
+	}
+
+	return path
+}
+
+func TestVerify(t *testing.T) {
+	secret := []byte("secret")
+	signing := newRSAKey(t)
+	other := newRSAKey(t)
+	jwks := writeJWKS(t, jwksDocument(map[string]*rsa.PrivateKey{"primary": signing}))
+
+	publicKey, err := x509.MarshalPKIXPublicKey(&signing.PublicKey)
+
+	if err != nil {
+		t.Fatalf("failed to encode the public key: %v", err)
+	}
+
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})
+
+	now := time.Now()
+	hs256 := map[string]any{"alg": "HS256", "typ": "JWT"}
+	rs256 := map[string]any{"alg": "RS256", "typ": "JWT", "kid": "primary"}
+
+	claims := func(overrides map[string]any) map[string]any {
+		values := map[string]any{
+			"sub": "u1",
+			"iss": "https://issuer.example.com",
+			"aud": "api",
+			"exp": now.Add(time.Hour).Unix(),
+			"iat": now.Unix(),
+		}
+
+		for name, value := range overrides {
+			if value == nil {
+				delete(values, name)
+
+				continue
+			}
+
+			values[name] = value
+		}
+
+		return values
+	}
+
+	hmacOnly := Config{Secret: secret, Issuer: "https://issuer.example.com", Audience: "api", ClockSkew: 30 * time.Second}
+	jwksOnly := Config{JWKS: jwks, Issuer: "https://issuer.example.com", Audience: "api", ClockSkew: 30 * time.Second}
+	both := Config{Secret: secret, JWKS: jwks, Audience: "api", ClockSkew: 30 * time.Second}
+
+	tests := []struct {
+		name    string
+		config  Config
+		token   func() string
+		wantErr string
+	}{
+		{
+			name:   "valid HS256",
+			config: hmacOnly,
+			token:  func() string { return signHS256(t, secret, hs256, claims(nil)) },
+		},
+		{
+			name:    "HS256 with the wrong secret",
+			config:  hmacOnly,
+			token:   func() string { return signHS256(t, []byte("guess"), hs256, claims(nil)) },
+			wantErr: "signature is invalid",
+		},
+		{
+			name:   "HS256 with a tampered payload",
+			config: hmacOnly,
+			token: func() string {
+				parts := strings.Split(signHS256(t, secret, hs256, claims(nil)), ".")
+
+				return parts[0] + "." + encodeSegment(t, claims(map[string]any{"sub": "admin"})) + "." + parts[2]
+			},
+			wantErr: "signature is invalid",
+		},
+		{
+			name:   "valid RS256",
+			config: jwksOnly,
+			token:  func() string { return signRS256(t, signing, rs256, claims(nil)) },
+		},
+		{
+			name:    "RS256 signed by another key",
+			config:  jwksOnly,
+			token:   func() string { return signRS256(t, other, rs256, claims(nil)) },
+			wantErr: "signature is invalid",
+		},
+		{
+			name:   "RS256 with an unknown kid",
+			config: jwksOnly,
+			token: func() string {
+				return signRS256(t, signing, map[string]any{"alg": "RS256", "kid": "retired"}, claims(nil))
+			},
+			wantErr: "unknown key",
+		},
+		{
+			name:   "alg none",
+			config: both,
+			token: func() string {
+				return encodeSegment(t, map[string]any{"alg": "none"}) + "." + encodeSegment(t, claims(nil)) + "."
+			},
+			wantErr: `algorithm "none" is not accepted`,
+		},
+		{
+			name:    "HS256 signed with the RSA public key",
+			config:  jwksOnly,
+			token:   func() string { return signHS256(t, publicPEM, hs256, claims(nil)) },
+			wantErr: `algorithm "HS256" is not accepted`,
+		},
+		{
+			name:    "HS256 signed with the RSA public key alongside a secret",
+			config:  both,
+			token:   func() string { return signHS256(t, publicPEM, hs256, claims(nil)) },
+			wantErr: "signature is invalid",
+		},
+		{
+			name:    "RS256 without a JWKS",
+			config:  hmacOnly,
+			token:   func() string { return signRS256(t, signing, rs256, claims(nil)) },
+			wantErr: `algorithm "RS256" is not accepted`,
+		},
+		{
+			name:    "not a JWT",
+			config:  hmacOnly,
+			token:   func() string { return "not-a-token" },
+			wantErr: "not a JWT",
+		},
+		{
+			name:    "missing expiry",
+			config:  hmacOnly,
+			token:   func() string { return signHS256(t, secret, hs256, claims(map[string]any{"exp": nil})) },
+			wantErr: "no expiry",
+		},
+		{
+			name:   "expired beyond the clock skew",
+			config: hmacOnly,
+			token: func() string {
+				return signHS256(t, secret, hs256, claims(map[string]any{"exp": now.Add(-time.Minute).Unix()}))
+			},
+			wantErr: "expired",
+		},
+		{
+			name:   "expired within the clock skew",
+			config: hmacOnly,
+			token: func() string {
+				return signHS256(t, secret, hs256, claims(map[string]any{"exp": now.Add(-10 * time.Second).Unix()}))
+			},
+		},
+		{
+			name:   "not valid yet beyond the clock skew",
+			config: hmacOnly,
+			token: func() string {
+				return signHS256(t, secret, hs256, claims(map[string]any{"nbf": now.Add(time.Minute).Unix()}))
+			},
+			wantErr: "not valid yet",
+		},
+		{
+			name:   "not valid yet within the clock skew",
+			config: hmacOnly,
+			token: func() string {
+				return signHS256(t, secret, hs256, claims(map[string]any{"nbf": now.Add(10 * time.Second).Unix()}))
+			},
+		},
+		{
+			name:   "issuer mismatch",
+			config: hmacOnly,
+			token: func() string {
+				return signHS256(t, secret, hs256, claims(map[string]any{"iss": "https://evil.example.com"}))
+			},
+			wantErr: "issued by",
+		},
+		{
+			name:    "missing issuer",
+			config:  hmacOnly,
+			token:   func() string { return signHS256(t, secret, hs256, claims(map[string]any{"iss": nil})) },
+			wantErr: "issued by",
+		},
+		{
+			name:    "audience mismatch",
+			config:  hmacOnly,
+			token:   func() string { return signHS256(t, secret, hs256, claims(map[string]any{"aud": "other"})) },
+			wantErr: "audience",
+		},
+		{
+			name:   "audience in a list",
+			config: hmacOnly,
+			token: func() string {
+				return signHS256(t, secret, hs256, claims(map[string]any{"aud": []string{"other", "api"}}))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			verifier, err := NewVerifier(test.config)
+
+			if err != nil {
+				t.Fatalf("failed to create the verifier: %v", err)
+			}
+
+			claims, err := verifier.Verify(context.Background(), test.token())
+
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", test.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if claims.Subject != "u1" {
+				t.Fatalf("expected subject u1, got %q", claims.Subject)
+			}
+		})
+	}
+}
+
+func TestNewVerifierRequiresConfiguration(t *testing.T) {
+	if _, err := NewVerifier(Config{}); err != ErrNotConfigured {
+		t.Fatalf("expected ErrNotConfigured, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type Cache interface {
+	Get(key string) (any, bool)
+	Set(key string, value any)
+	Delete(key string)
+	Stats() Stats
+}
+
+type Stats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Size      int    `json:"size"`
+}
+
+type entry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+type lru struct {
+	mutex    sync.Mutex
+	capacity int
+	ttl      time.Duration
+	items    map[string]*list.Element
+	order    *list.List
+	stats    Stats
+}
+
+func NewLRU(capacity int, ttl time.Duration) Cache {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &lru{
+		capacity: capacity,
+		ttl:      ttl,
+		items:    map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+func (l *lru) Get(key string) (any, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	element, exists := l.items[key]
+
+	if !exists {
+		l.stats.Misses++
+
+		return nil, false
+	}
+
+	item := element.Value.(*entry)
+
+	if !item.expiresAt.IsZero() && time.Now().After(item.expiresAt) {
+		l.remove(element)
+		l.stats.Misses++
+
+		return nil, false
+	}
+
+	l.order.MoveToFront(element)
+	l.stats.Hits++
+
+	return item.value, true
+}
+
+func (l *lru) Set(key string, value any) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	expiresAt := time.Time{}
+
+	if l.ttl > 0 {
+		expiresAt = time.Now().Add(l.ttl)
+	}
+
+	if element, exists := l.items[key]; exists {
+		element.Value = &entry{key: key, value: value, expiresAt: expiresAt}
+		l.order.MoveToFront(element)
+
+		return
+	}
+
+	l.items[key] = l.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+
+	for l.order.Len() > l.capacity {
+		l.remove(l.order.Back())
+		l.stats.Evictions++
+	}
+}
+
+func (l *lru) Delete(key string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if element, exists := l.items[key]; exists {
+		l.remove(element)
+	}
+}
+
+func (l *lru) Stats() Stats {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	stats := l.stats
+	stats.Size = l.order.Len()
+
+	return stats
+}
+
+func (l *lru) remove(element *list.Element) {
+	l.order.Remove(element)
+	delete(l.items, element.Value.(*entry).key)
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewLRU(2, 0)
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+
+	cache.Set("c", 3)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Fatal("expected b to be evicted as the least recently used entry")
+	}
+
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Fatalf("expected %s to be cached", key)
+		}
+	}
+
+	cache.Set("a", 10)
+	cache.Set("d", 4)
+
+	if value, ok := cache.Get("a"); !ok || value != 10 {
+		t.Fatalf("expected the overwritten a to survive, got %v %v", value, ok)
+	}
+
+	if _, ok := cache.Get("c"); ok {
+		t.Fatal("expected c to be evicted after a was refreshed")
+	}
+
+	if stats := cache.Stats(); stats.Evictions != 2 || stats.Size != 2 {
+		t.Fatalf("expected 2 evictions and 2 entries, got %+v", stats)
+	}
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	cache := NewLRU(10, 20*time.Millisecond)
+
+	cache.Set("a", 1)
+
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("expected a to be cached before it expires")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Fatal("expected a to have expired")
+	}
+
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 1 || stats.Size != 0 {
+		t.Fatalf("expected the expired entry to count as a miss and be removed, got %+v", stats)
+	}
+
+	cache.Set("b", 2)
+	time.Sleep(15 * time.Millisecond)
+	cache.Set("b", 3)
+	time.Sleep(15 * time.Millisecond)
+
+	if value, ok := cache.Get("b"); !ok || value != 3 {
+		t.Fatalf("expected overwriting to reset the expiry, got %v %v", value, ok)
+	}
+}
+
+func TestLRUCountsAccurately(t *testing.T) {
+	cache := NewLRU(3, 0)
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		cache.Set(key, key)
+	}
+
+	for _, key := range []string{"a", "b", "c", "d", "d", "x"} {
+		cache.Get(key)
+	}
+
+	cache.Delete("c")
+	cache.Delete("missing")
+
+	want := Stats{Hits: 4, Misses: 2, Evictions: 1, Size: 2}
+
+	if stats := cache.Stats(); stats != want {
+		t.Fatalf("expected %+v, got %+v", want, stats)
+	}
+}
+
+func TestLRUKeepsAtLeastOneEntry(t *testing.T) {
+	cache := NewLRU(0, 0)
+
+	cache.Set("a", 1)
+
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("expected a zero capacity cache to hold one entry")
+	}
+}
@@ -0,0 +1,64 @@
+package crud
+
+import (
+	"github.com/connor-davis/dynamic-crud/internal/auth"
+	"github.com/connor-davis/dynamic-crud/internal/routing"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (c *crudApi[T]) AssignAuthentication(authenticator routing.Authenticator, operations ...Operation) CrudApi[T] {
+	for _, operation := range operationsOrAll(operations) {
+		c.authenticators[operation] = append(c.authenticators[operation], authenticator)
+	}
+
+	return c
+}
+
+func (c *crudApi[T]) middlewares(operation Operation) []fiber.Handler {
+	middlewares := []fiber.Handler{cancellation()}
+
+	if authenticators := c.authenticators[operation]; len(authenticators) > 0 {
+		middlewares = append(middlewares, auth.Required(authenticators...))
+	}
+
+	if c.model.tenantField() != nil {
+		middlewares = append(middlewares, c.tenancy())
+	}
+
+	return middlewares
+}
+
+func (c *crudApi[T]) security(operation Operation) *openapi3.SecurityRequirements {
+	authenticators := c.authenticators[operation]
+
+	if len(authenticators) == 0 {
+		return nil
+	}
+
+	requirements := openapi3.NewSecurityRequirements()
+
+	for _, authenticator := range authenticators {
+		name, _ := authenticator.SecurityScheme()
+
+		requirements.With(openapi3.NewSecurityRequirement().Authenticate(name))
+	}
+
+	return requirements
+}
+
+func (c *crudApi[T]) securitySchemes() openapi3.SecuritySchemes {
+	securitySchemes := openapi3.SecuritySchemes{}
+
+	for _, authenticators := range c.authenticators {
+		for _, authenticator := range authenticators {
+			name, scheme := authenticator.SecurityScheme()
+
+			securitySchemes[name] = &openapi3.SecuritySchemeRef{
+				Value: scheme,
+			}
+		}
+	}
+
+	return securitySchemes
+}
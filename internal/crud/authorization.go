@@ -0,0 +1,111 @@
+package crud
+
+import (
+	"context"
+	"errors"
+
+	"github.com/connor-davis/dynamic-crud/internal/auth"
+	"gorm.io/gorm/clause"
+)
+
+type Policy[T any] func(ctx context.Context, claims *auth.Claims, entity *T) error
+
+type Scope func(ctx context.Context, claims *auth.Claims) (clause.Expression, error)
+
+func (c *crudApi[T]) Authorize(operation Operation, policy Policy[T]) CrudApi[T] {
+	c.policies[operation] = append(c.policies[operation], policy)
+
+	return c
+}
+
+func (c *crudApi[T]) AssignScope(scope Scope) CrudApi[T] {
+	c.scopes = append(c.scopes, scope)
+
+	return c
+}
+
+func (c *crudApi[T]) guarded(operation Operation) bool {
+	return len(c.policies[operation]) > 0 || len(c.scopes) > 0
+}
+
+func (c *crudApi[T]) authorize(ctx context.Context, operation Operation, entity *T) error {
+	claims, _ := auth.ClaimsFrom(ctx)
+
+	for _, policy := range c.policies[operation] {
+		if err := policy(ctx, claims, entity); err != nil {
+			var crudError *Error
+
+			if errors.As(err, &crudError) {
+				return err
+			}
+
+			return &Error{
+				Kind:   KindForbidden,
+				Detail: err.Error(),
+				Err:    err,
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *crudApi[T]) scope(ctx context.Context, query *Query) (*Query, error) {
+	if query == nil {
+		query = &Query{}
+	}
+
+	claims, _ := auth.ClaimsFrom(ctx)
+
+	for _, scope := range c.scopes {
+		expression, err := scope(ctx, claims)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if expression != nil {
+			query.Scopes = append(query.Scopes, expression)
+		}
+	}
+
+	return query, nil
+}
+
+func (c *crudApi[T]) load(ctx context.Context, operation Operation, entityId any, query *Query, entity *T) error {
+	query, err := c.scope(ctx, query)
+
+	if err != nil {
+		return err
+	}
+
+	if err := c.crud.FindOne(ctx, entityId, query, entity); err != nil {
+		return err
+	}
+
+	return c.authorize(ctx, operation, entity)
+}
+
+func (c *crudApi[T]) findTargets(ctx context.Context, entityIds []any) ([]T, error) {
+	query, err := c.scope(ctx, &Query{
+		Scopes: []clause.Expression{
+			clause.IN{Column: clause.PrimaryColumn, Values: entityIds},
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	entities := []T{}
+
+	if len(entityIds) == 0 {
+		return entities, nil
+	}
+
+	if err := c.crud.FindAll(ctx, query, &entities); err != nil {
+		return nil, err
+	}
+
+	return entities, nil
+}
@@ -0,0 +1,106 @@
+package crud
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type BackgroundCrud[T any] interface {
+	Create(entity *T) error
+	CreateBatch(entities *[]T, batchSize int) error
+	CreateIfAbsent(entity *T, columns []string) (bool, error)
+	Update(entityId any, entity *T) error
+	Patch(entityId any, entity *T, columns []string) error
+	Delete(entityId any, entity *T) error
+	HardDelete(entityId any, entity *T) error
+	DeleteBatch(entityIds []any) (int64, error)
+	Restore(entityId any, entity *T) error
+	FindOne(entityId any, query *Query, entity *T) error
+	FindAll(query *Query, entities *[]T) error
+	FindByIds(entityIds []any, entities *[]T) error
+	FindPage(query *Query, entities *[]T) (*Page, error)
+	FindTrash(query *Query, entities *[]T) (*Page, error)
+}
+
+type backgroundCrud[T any] struct {
+	ctx  context.Context
+	crud Crud[T]
+}
+
+func NewBackgroundCrud[T any](crud Crud[T]) BackgroundCrud[T] {
+	return &backgroundCrud[T]{
+		ctx:  context.Background(),
+		crud: crud,
+	}
+}
+
+func NewTenantCrud[T any](crud Crud[T], tenantId uuid.UUID) BackgroundCrud[T] {
+	return &backgroundCrud[T]{
+		ctx:  WithTenant(context.Background(), tenantId),
+		crud: crud,
+	}
+}
+
+func NewSystemCrud[T any](crud Crud[T]) BackgroundCrud[T] {
+	return &backgroundCrud[T]{
+		ctx:  WithSystem(context.Background()),
+		crud: crud,
+	}
+}
+
+func (b *backgroundCrud[T]) Create(entity *T) error {
+	return b.crud.Create(b.ctx, entity)
+}
+
+func (b *backgroundCrud[T]) CreateBatch(entities *[]T, batchSize int) error {
+	return b.crud.CreateBatch(b.ctx, entities, batchSize)
+}
+
+func (b *backgroundCrud[T]) CreateIfAbsent(entity *T, columns []string) (bool, error) {
+	return b.crud.CreateIfAbsent(b.ctx, entity, columns)
+}
+
+func (b *backgroundCrud[T]) Update(entityId any, entity *T) error {
+	return b.crud.Update(b.ctx, entityId, entity)
+}
+
+func (b *backgroundCrud[T]) Patch(entityId any, entity *T, columns []string) error {
+	return b.crud.Patch(b.ctx, entityId, entity, columns)
+}
+
+func (b *backgroundCrud[T]) Delete(entityId any, entity *T) error {
+	return b.crud.Delete(b.ctx, entityId, entity)
+}
+
+func (b *backgroundCrud[T]) HardDelete(entityId any, entity *T) error {
+	return b.crud.HardDelete(b.ctx, entityId, entity)
+}
+
+func (b *backgroundCrud[T]) DeleteBatch(entityIds []any) (int64, error) {
+	return b.crud.DeleteBatch(b.ctx, entityIds)
+}
+
+func (b *backgroundCrud[T]) Restore(entityId any, entity *T) error {
+	return b.crud.Restore(b.ctx, entityId, entity)
+}
+
+func (b *backgroundCrud[T]) FindOne(entityId any, query *Query, entity *T) error {
+	return b.crud.FindOne(b.ctx, entityId, query, entity)
+}
+
+func (b *backgroundCrud[T]) FindAll(query *Query, entities *[]T) error {
+	return b.crud.FindAll(b.ctx, query, entities)
+}
+
+func (b *backgroundCrud[T]) FindByIds(entityIds []any, entities *[]T) error {
+	return b.crud.FindByIds(b.ctx, entityIds, entities)
+}
+
+func (b *backgroundCrud[T]) FindPage(query *Query, entities *[]T) (*Page, error) {
+	return b.crud.FindPage(b.ctx, query, entities)
+}
+
+func (b *backgroundCrud[T]) FindTrash(query *Query, entities *[]T) (*Page, error) {
+	return b.crud.FindTrash(b.ctx, query, entities)
+}
@@ -0,0 +1,903 @@
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/connor-davis/dynamic-crud/internal/routing"
+	"github.com/connor-davis/dynamic-crud/internal/routing/schemas"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+)
+
+type BulkMode string
+
+const (
+	BulkAtomic  BulkMode = "atomic"
+	BulkPartial BulkMode = "partial"
+)
+
+const (
+	DefaultMaxBatchSize = 1000
+	insertBatchSize     = 100
+)
+
+type BulkResult struct {
+	Index  int      `json:"index"`
+	Status int      `json:"status"`
+	Item   any      `json:"item,omitempty"`
+	Error  *Problem `json:"error,omitempty"`
+}
+
+type bulkItems struct {
+	Items []json.RawMessage `json:"items"`
+}
+
+type bulkIds struct {
+	Ids     []string          `json:"ids"`
+	IfMatch map[string]string `json:"ifMatch"`
+}
+
+func parseBulkMode(ctx *fiber.Ctx) (BulkMode, error) {
+	mode := BulkMode(ctx.Query("mode", string(BulkAtomic)))
+
+	if mode != BulkAtomic && mode != BulkPartial {
+		return "", fmt.Errorf("mode must be %s or %s", BulkAtomic, BulkPartial)
+	}
+
+	return mode, nil
+}
+
+func (c *crudApi[T]) checkBatchSize(count int) error {
+	if count == 0 {
+		return fmt.Errorf("the request must contain at least one item")
+	}
+
+	if count > c.maxBatchSize {
+		return fmt.Errorf("the request must contain at most %d items", c.maxBatchSize)
+	}
+
+	return nil
+}
+
+func (c *crudApi[T]) decodeBulkItems(body []byte) ([]json.RawMessage, error) {
+	payload := bulkItems{}
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("the request body must be a JSON object with an items array: %w", err)
+	}
+
+	if err := c.checkBatchSize(len(payload.Items)); err != nil {
+		return nil, err
+	}
+
+	return payload.Items, nil
+}
+
+func (c *crudApi[T]) bulkPartial(ctx context.Context, count int, failures map[int]error, apply func(ctx context.Context, index int) error) error {
+	return transaction(ctx, c.storage, func(txCtx context.Context) error {
+		for index := 0; index < count; index++ {
+			if failures[index] != nil {
+				continue
+			}
+
+			if err := savepoint(txCtx, func(itemCtx context.Context) error {
+				return apply(itemCtx, index)
+			}); err != nil {
+				failures[index] = err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (c *crudApi[T]) bulkFound(ctx context.Context, entities []T, failures map[int]error) error {
+	for index := range entities {
+		if failures[index] != nil {
+			continue
+		}
+
+		if err := runHooks(ctx, c.hooks.afterFind, &entities[index]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *crudApi[T]) bulkReload(ctx context.Context, ids []any, entities []T, failures map[int]error) error {
+	targets := []any{}
+
+	for index, id := range ids {
+		if failures[index] == nil && id != nil {
+			targets = append(targets, id)
+		}
+	}
+
+	found := []T{}
+
+	if err := c.crud.FindByIds(ctx, targets, &found); err != nil {
+		return err
+	}
+
+	reloaded := map[string]T{}
+
+	for _, entity := range found {
+		reloaded[fmt.Sprint(c.model.primaryKey(&entity))] = entity
+	}
+
+	for index, id := range ids {
+		if entity, exists := reloaded[fmt.Sprint(id)]; exists && failures[index] == nil {
+			entities[index] = entity
+		}
+	}
+
+	return nil
+}
+
+func bulkResults(count int, failures map[int]error, status int, item func(index int) any) []BulkResult {
+	results := []BulkResult{}
+
+	for index := 0; index < count; index++ {
+		if err := failures[index]; err != nil {
+			problem := newProblem(err)
+
+			results = append(results, BulkResult{
+				Index:  index,
+				Status: problem.Status,
+				Error:  problem,
+			})
+
+			continue
+		}
+
+		result := BulkResult{
+			Index:  index,
+			Status: status,
+		}
+
+		if item != nil {
+			result.Item = item(index)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func bulkItemError(index int, err error) error {
+	crudError := asError(err)
+	fieldErrors := []FieldError{}
+
+	for _, fieldError := range crudError.Errors {
+		fieldError.Path = fmt.Sprintf("/items/%d%s", index, strings.TrimSuffix(fieldError.Path, "/"))
+		fieldErrors = append(fieldErrors, fieldError)
+	}
+
+	if len(fieldErrors) == 0 {
+		fieldErrors = nil
+	}
+
+	return &Error{
+		Kind:   crudError.Kind,
+		Detail: fmt.Sprintf("Item %d: %s", index, crudError.Detail),
+		Errors: fieldErrors,
+		Err:    err,
+	}
+}
+
+func bulkFailure(failures map[int]error) error {
+	indexes := []int{}
+
+	for index := range failures {
+		indexes = append(indexes, index)
+	}
+
+	slices.Sort(indexes)
+
+	fieldErrors := []FieldError{}
+
+	for _, index := range indexes {
+		itemError := bulkItemError(index, failures[index]).(*Error)
+
+		if itemError.Kind != KindValidation {
+			return itemError
+		}
+
+		fieldErrors = append(fieldErrors, itemError.Errors...)
+	}
+
+	return NewValidationError("One or more items are invalid.", fieldErrors)
+}
+
+func (c *crudApi[T]) bulkModeParameter() *openapi3.ParameterRef {
+	return &openapi3.ParameterRef{
+		Value: openapi3.NewQueryParameter("mode").
+			WithDescription("atomic applies every item in one transaction and fails as a whole, partial applies the valid items and reports a status per item.").
+			WithSchema(openapi3.NewStringSchema().
+				WithEnum(string(BulkAtomic), string(BulkPartial)).
+				WithDefault(string(BulkAtomic))),
+	}
+}
+
+func (c *crudApi[T]) BulkCreateRoute() routing.Route {
+	responses := openapi3.NewResponses()
+
+	responses.Set("201", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription(fmt.Sprintf("%s's created successfully in atomic mode.", c.name)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchemaRef(c.bulkRef()),
+			}),
+	})
+
+	responses.Set("207", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription("The status of every item in partial mode.").
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchemaRef(c.bulkResultRef()),
+			}),
+	})
+
+	responses.Set("400", schemas.NewErrorResponse("Bad Request"))
+	responses.Set("401", schemas.NewErrorResponse("Unauthorized"))
+	responses.Set("403", schemas.NewErrorResponse("Forbidden"))
+	responses.Set("409", schemas.NewErrorResponse("Conflict"))
+	responses.Set("422", schemas.NewValidationErrorResponse("Unprocessable Entity"))
+	responses.Set("500", schemas.NewErrorResponse("Internal Server Error"))
+	responses.Set("503", schemas.NewErrorResponse("Service Unavailable"))
+	responses.Set("504", schemas.NewErrorResponse("Gateway Timeout"))
+
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     fmt.Sprintf("Bulk create %ss", c.name),
+			Description: fmt.Sprintf("This endpoint creates up to %d %ss in one request.", c.maxBatchSize, strings.ToLower(c.name)),
+			Tags:        []string{fmt.Sprintf("%ss", c.name)},
+			Parameters: append([]*openapi3.ParameterRef{
+				c.bulkModeParameter(),
+			}, c.tenantParameters()...),
+			RequestBody: &openapi3.RequestBodyRef{
+				Value: openapi3.NewRequestBody().
+					WithRequired(true).
+					WithJSONSchemaRef(c.bulkCreateRef()).
+					WithDescription(fmt.Sprintf("Payloads to create new %ss.", strings.ToLower(c.name))),
+			},
+			Responses: responses,
+			Security:  c.security(OperationCreate),
+		},
+		Entity:          c.name,
+		Schemas:         c.schemas(),
+		SecuritySchemes: c.securitySchemes(),
+		Method:          routing.POST,
+		Path:            fmt.Sprintf("/%ss/bulk", strings.ToLower(c.name)),
+		Middlewares:     c.middlewares(OperationCreate),
+		Handler: func(ctx *fiber.Ctx) error {
+			queryCtx, cancel := c.queryContext(ctx, OperationCreate)
+			defer cancel()
+
+			mode, err := parseBulkMode(ctx)
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			items, err := c.decodeBulkItems(ctx.Body())
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			entities := make([]T, len(items))
+			failures := map[int]error{}
+
+			for index, item := range items {
+				fieldErrors, err := c.decodeBody(item, c.create, &entities[index])
+
+				if err != nil {
+					failures[index] = NewError(KindBadRequest, err.Error())
+
+					continue
+				}
+
+				if len(fieldErrors) > 0 {
+					failures[index] = NewValidationError(fmt.Sprintf("The %s payload is invalid.", strings.ToLower(c.name)), fieldErrors)
+
+					continue
+				}
+
+				if err := c.authorize(queryCtx, OperationCreate, &entities[index]); err != nil {
+					failures[index] = err
+
+					continue
+				}
+
+				if err := c.guardWrites(queryCtx, nil, &entities[index]); err != nil {
+					failures[index] = err
+				}
+			}
+
+			if mode == BulkPartial {
+				if err := c.bulkPartial(queryCtx, len(items), failures, func(itemCtx context.Context, index int) error {
+					if err := runHooks(itemCtx, c.hooks.beforeCreate, &entities[index]); err != nil {
+						return err
+					}
+
+					if err := c.crud.Create(itemCtx, &entities[index]); err != nil {
+						return err
+					}
+
+					return runHooks(itemCtx, c.hooks.afterCreate, &entities[index])
+				}); err != nil {
+					return err
+				}
+
+				if err := c.bulkFound(queryCtx, entities, failures); err != nil {
+					return err
+				}
+
+				presented, err := c.presentAll(queryCtx, entities, nil)
+
+				if err != nil {
+					return err
+				}
+
+				return ctx.Status(fiber.StatusMultiStatus).JSON(fiber.Map{
+					"results": bulkResults(len(items), failures, fiber.StatusCreated, func(index int) any {
+						return presented[index]
+					}),
+				})
+			}
+
+			if len(failures) > 0 {
+				return bulkFailure(failures)
+			}
+
+			if err := transaction(queryCtx, c.storage, func(txCtx context.Context) error {
+				for index := range entities {
+					if err := runHooks(txCtx, c.hooks.beforeCreate, &entities[index]); err != nil {
+						return bulkItemError(index, err)
+					}
+				}
+
+				if err := c.crud.CreateBatch(txCtx, &entities, insertBatchSize); err != nil {
+					return err
+				}
+
+				for index := range entities {
+					if err := runHooks(txCtx, c.hooks.afterCreate, &entities[index]); err != nil {
+						return bulkItemError(index, err)
+					}
+				}
+
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			if err := c.bulkFound(queryCtx, entities, failures); err != nil {
+				return err
+			}
+
+			presented, err := c.presentAll(queryCtx, entities, nil)
+
+			if err != nil {
+				return err
+			}
+
+			return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{
+				"items": presented,
+			})
+		},
+	}
+}
+
+func (c *crudApi[T]) BulkPatchRoute() routing.Route {
+	responses := openapi3.NewResponses()
+
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription(fmt.Sprintf("%s's patched successfully in atomic mode.", c.name)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchemaRef(c.bulkRef()),
+			}),
+	})
+
+	responses.Set("207", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription("The status of every item in partial mode.").
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchemaRef(c.bulkResultRef()),
+			}),
+	})
+
+	responses.Set("400", schemas.NewErrorResponse("Bad Request"))
+	responses.Set("401", schemas.NewErrorResponse("Unauthorized"))
+	responses.Set("403", schemas.NewErrorResponse("Forbidden"))
+	responses.Set("404", schemas.NewErrorResponse("Not Found"))
+	responses.Set("409", schemas.NewErrorResponse("Conflict"))
+	responses.Set("412", schemas.NewErrorResponse("Precondition Failed"))
+	responses.Set("422", schemas.NewValidationErrorResponse("Unprocessable Entity"))
+	responses.Set("428", schemas.NewErrorResponse("Precondition Required"))
+	responses.Set("500", schemas.NewErrorResponse("Internal Server Error"))
+	responses.Set("503", schemas.NewErrorResponse("Service Unavailable"))
+	responses.Set("504", schemas.NewErrorResponse("Gateway Timeout"))
+
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     fmt.Sprintf("Bulk patch %ss", c.name),
+			Description: fmt.Sprintf("This endpoint applies a JSON merge patch to up to %d %ss in one request. Every item must contain the id of the %s to patch.", c.maxBatchSize, strings.ToLower(c.name), strings.ToLower(c.name)),
+			Tags:        []string{fmt.Sprintf("%ss", c.name)},
+			Parameters: append([]*openapi3.ParameterRef{
+				c.bulkModeParameter(),
+			}, c.tenantParameters()...),
+			RequestBody: &openapi3.RequestBodyRef{
+				Value: openapi3.NewRequestBody().
+					WithRequired(true).
+					WithJSONSchemaRef(c.bulkPatchRef()).
+					WithDescription(fmt.Sprintf("Merge patches to apply to existing %ss.", strings.ToLower(c.name))),
+			},
+			Responses: responses,
+			Security:  c.security(OperationPatch),
+		},
+		Entity:          c.name,
+		Schemas:         c.schemas(),
+		SecuritySchemes: c.securitySchemes(),
+		Method:          routing.PATCH,
+		Path:            fmt.Sprintf("/%ss/bulk", strings.ToLower(c.name)),
+		Middlewares:     c.middlewares(OperationPatch),
+		Handler: func(ctx *fiber.Ctx) error {
+			queryCtx, cancel := c.queryContext(ctx, OperationPatch)
+			defer cancel()
+
+			mode, err := parseBulkMode(ctx)
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			items, err := c.decodeBulkItems(ctx.Body())
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			ids := make([]any, len(items))
+			patches := make([][]byte, len(items))
+			versions := make([][]any, len(items))
+			failures := map[int]error{}
+
+			for index, item := range items {
+				payload := map[string]any{}
+
+				if err := json.Unmarshal(item, &payload); err != nil {
+					failures[index] = NewError(KindBadRequest, fmt.Sprintf("the item must be a JSON object: %s", err.Error()))
+
+					continue
+				}
+
+				rawId, _ := payload["id"].(string)
+
+				id, err := c.model.parseId(rawId)
+
+				if err != nil {
+					failures[index] = NewError(KindBadRequest, err.Error())
+
+					continue
+				}
+
+				ifMatch, ok := payload[IfMatchProperty].(string)
+
+				if _, exists := payload[IfMatchProperty]; exists && !ok {
+					failures[index] = NewError(KindBadRequest, fmt.Sprintf("the %s property must be a string", IfMatchProperty))
+
+					continue
+				}
+
+				versions[index], err = c.expectedVersions(strings.TrimSpace(ifMatch), fmt.Sprintf("The %s property", IfMatchProperty))
+
+				if err != nil {
+					failures[index] = err
+
+					continue
+				}
+
+				delete(payload, "id")
+				delete(payload, IfMatchProperty)
+
+				patch, err := json.Marshal(payload)
+
+				if err != nil {
+					failures[index] = err
+
+					continue
+				}
+
+				ids[index] = id
+				patches[index] = patch
+			}
+
+			if duplicates := duplicateIds(ids); len(duplicates) > 0 {
+				return NewError(KindBadRequest, fmt.Sprintf("duplicate ids are not allowed: %s", strings.Join(duplicates, ", ")))
+			}
+
+			found, err := c.findTargets(queryCtx, slices.DeleteFunc(slices.Clone(ids), func(id any) bool { return id == nil }))
+
+			if err != nil {
+				return err
+			}
+
+			existing := map[string]T{}
+
+			for _, entity := range found {
+				existing[fmt.Sprint(c.model.primaryKey(&entity))] = entity
+			}
+
+			entities := make([]T, len(items))
+			columns := make([][]string, len(items))
+
+			for index := range items {
+				if failures[index] != nil {
+					continue
+				}
+
+				entity, exists := existing[fmt.Sprint(ids[index])]
+
+				if !exists {
+					failures[index] = &NotFoundError{Entity: c.name, Id: ids[index]}
+
+					continue
+				}
+
+				entities[index] = entity
+
+				if err := c.checkVersion(&entities[index], versions[index]); err != nil {
+					failures[index] = err
+
+					continue
+				}
+
+				if err := c.authorize(queryCtx, OperationPatch, &entities[index]); err != nil {
+					failures[index] = err
+
+					continue
+				}
+
+				itemColumns, fieldErrors, err := c.patchEntity(queryCtx, MergePatchContentType, patches[index], &entities[index])
+
+				if err != nil {
+					failures[index] = NewError(KindBadRequest, err.Error())
+
+					continue
+				}
+
+				if len(fieldErrors) > 0 {
+					failures[index] = NewValidationError(fmt.Sprintf("The patched %s is invalid.", strings.ToLower(c.name)), fieldErrors)
+
+					continue
+				}
+
+				if err := c.guardWrites(queryCtx, &entity, &entities[index]); err != nil {
+					failures[index] = err
+
+					continue
+				}
+
+				columns[index] = itemColumns
+			}
+
+			apply := func(itemCtx context.Context, index int) error {
+				prepared := entities[index]
+
+				if err := runHooks(itemCtx, c.hooks.beforeUpdate, &entities[index]); err != nil {
+					return err
+				}
+
+				if err := c.crud.Patch(withVersions(itemCtx, versions[index]), ids[index], &entities[index], mergeColumns(columns[index], c.model.changedColumns(&prepared, &entities[index]))); err != nil {
+					return err
+				}
+
+				return runHooks(itemCtx, c.hooks.afterUpdate, &entities[index])
+			}
+
+			if mode == BulkPartial {
+				if err := c.bulkPartial(queryCtx, len(items), failures, apply); err != nil {
+					return err
+				}
+
+				if err := c.bulkReload(queryCtx, ids, entities, failures); err != nil {
+					return err
+				}
+
+				if err := c.bulkFound(queryCtx, entities, failures); err != nil {
+					return err
+				}
+
+				presented, err := c.presentAll(queryCtx, entities, nil)
+
+				if err != nil {
+					return err
+				}
+
+				return ctx.Status(fiber.StatusMultiStatus).JSON(fiber.Map{
+					"results": bulkResults(len(items), failures, fiber.StatusOK, func(index int) any {
+						return presented[index]
+					}),
+				})
+			}
+
+			if len(failures) > 0 {
+				return bulkFailure(failures)
+			}
+
+			if err := transaction(queryCtx, c.storage, func(txCtx context.Context) error {
+				for index := range entities {
+					if err := apply(txCtx, index); err != nil {
+						return bulkItemError(index, err)
+					}
+				}
+
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			if err := c.bulkReload(queryCtx, ids, entities, failures); err != nil {
+				return err
+			}
+
+			if err := c.bulkFound(queryCtx, entities, failures); err != nil {
+				return err
+			}
+
+			presented, err := c.presentAll(queryCtx, entities, nil)
+
+			if err != nil {
+				return err
+			}
+
+			return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+				"items": presented,
+			})
+		},
+	}
+}
+
+func (c *crudApi[T]) BulkDeleteRoute() routing.Route {
+	responses := openapi3.NewResponses()
+
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription(fmt.Sprintf("%s's deleted successfully in atomic mode.", c.name)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchemaRef(schemas.Ref("BulkDeleteResult", schemas.BulkDeleteSchema)),
+			}),
+	})
+
+	responses.Set("207", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription("The status of every id in partial mode.").
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchemaRef(c.bulkResultRef()),
+			}),
+	})
+
+	responses.Set("400", schemas.NewErrorResponse("Bad Request"))
+	responses.Set("401", schemas.NewErrorResponse("Unauthorized"))
+	responses.Set("403", schemas.NewErrorResponse("Forbidden"))
+	responses.Set("404", schemas.NewErrorResponse("Not Found"))
+	responses.Set("409", schemas.NewErrorResponse("Conflict"))
+	responses.Set("412", schemas.NewErrorResponse("Precondition Failed"))
+	responses.Set("428", schemas.NewErrorResponse("Precondition Required"))
+	responses.Set("500", schemas.NewErrorResponse("Internal Server Error"))
+	responses.Set("503", schemas.NewErrorResponse("Service Unavailable"))
+	responses.Set("504", schemas.NewErrorResponse("Gateway Timeout"))
+
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     fmt.Sprintf("Bulk delete %ss", c.name),
+			Description: fmt.Sprintf("This endpoint deletes up to %d %ss in one request.", c.maxBatchSize, strings.ToLower(c.name)),
+			Tags:        []string{fmt.Sprintf("%ss", c.name)},
+			Parameters: append([]*openapi3.ParameterRef{
+				c.bulkModeParameter(),
+			}, c.tenantParameters()...),
+			RequestBody: &openapi3.RequestBodyRef{
+				Value: openapi3.NewRequestBody().
+					WithRequired(true).
+					WithJSONSchemaRef(c.bulkDeleteRef()).
+					WithDescription(fmt.Sprintf("The ids of the %ss to delete.", strings.ToLower(c.name))),
+			},
+			Responses: responses,
+			Security:  c.security(OperationDelete),
+		},
+		Entity:          c.name,
+		Schemas:         c.schemas(),
+		SecuritySchemes: c.securitySchemes(),
+		Method:          routing.DELETE,
+		Path:            fmt.Sprintf("/%ss/bulk", strings.ToLower(c.name)),
+		Middlewares:     c.middlewares(OperationDelete),
+		Handler: func(ctx *fiber.Ctx) error {
+			queryCtx, cancel := c.queryContext(ctx, OperationDelete)
+			defer cancel()
+
+			mode, err := parseBulkMode(ctx)
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			payload := bulkIds{}
+
+			if err := json.Unmarshal(ctx.Body(), &payload); err != nil {
+				return NewError(KindBadRequest, fmt.Sprintf("the request body must be a JSON object with an ids array: %s", err.Error()))
+			}
+
+			if err := c.checkBatchSize(len(payload.Ids)); err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			ids := make([]any, len(payload.Ids))
+			versions := make([][]any, len(payload.Ids))
+			failures := map[int]error{}
+
+			for index, rawId := range payload.Ids {
+				id, err := c.model.parseId(rawId)
+
+				if err != nil {
+					failures[index] = NewError(KindBadRequest, err.Error())
+
+					continue
+				}
+
+				versions[index], err = c.expectedVersions(strings.TrimSpace(payload.IfMatch[rawId]), fmt.Sprintf("The %s entry for %s", IfMatchProperty, rawId))
+
+				if err != nil {
+					failures[index] = err
+
+					continue
+				}
+
+				ids[index] = id
+			}
+
+			if duplicates := duplicateIds(ids); len(duplicates) > 0 {
+				return NewError(KindBadRequest, fmt.Sprintf("duplicate ids are not allowed: %s", strings.Join(duplicates, ", ")))
+			}
+
+			found, err := c.findTargets(queryCtx, slices.DeleteFunc(slices.Clone(ids), func(id any) bool { return id == nil }))
+
+			if err != nil {
+				return err
+			}
+
+			existing := map[string]T{}
+
+			for _, entity := range found {
+				existing[fmt.Sprint(c.model.primaryKey(&entity))] = entity
+			}
+
+			entities := make([]T, len(ids))
+
+			for index := range ids {
+				if failures[index] != nil {
+					continue
+				}
+
+				entity, exists := existing[fmt.Sprint(ids[index])]
+
+				if !exists {
+					failures[index] = &NotFoundError{Entity: c.name, Id: ids[index]}
+
+					continue
+				}
+
+				entities[index] = entity
+
+				if err := c.checkVersion(&entities[index], versions[index]); err != nil {
+					failures[index] = err
+
+					continue
+				}
+
+				if err := c.authorize(queryCtx, OperationDelete, &entities[index]); err != nil {
+					failures[index] = err
+				}
+			}
+
+			if mode == BulkPartial {
+				if err := c.bulkPartial(queryCtx, len(ids), failures, func(itemCtx context.Context, index int) error {
+					if err := runHooks(itemCtx, c.hooks.beforeDelete, &entities[index]); err != nil {
+						return err
+					}
+
+					if err := c.crud.Delete(withVersions(itemCtx, versions[index]), ids[index], &entities[index]); err != nil {
+						return err
+					}
+
+					return runHooks(itemCtx, c.hooks.afterDelete, &entities[index])
+				}); err != nil {
+					return err
+				}
+
+				return ctx.Status(fiber.StatusMultiStatus).JSON(fiber.Map{
+					"results": bulkResults(len(ids), failures, fiber.StatusOK, nil),
+				})
+			}
+
+			if len(failures) > 0 {
+				return bulkFailure(failures)
+			}
+
+			var deleted int64
+
+			if err := transaction(queryCtx, c.storage, func(txCtx context.Context) error {
+				for index := range entities {
+					if err := runHooks(txCtx, c.hooks.beforeDelete, &entities[index]); err != nil {
+						return bulkItemError(index, err)
+					}
+				}
+
+				if slices.ContainsFunc(versions, func(expected []any) bool { return expected != nil }) {
+					for index := range entities {
+						if err := c.crud.Delete(withVersions(txCtx, versions[index]), ids[index], &entities[index]); err != nil {
+							return bulkItemError(index, err)
+						}
+					}
+
+					deleted = int64(len(entities))
+				} else {
+					deleted, err = c.crud.DeleteBatch(txCtx, ids)
+
+					if err != nil {
+						return err
+					}
+				}
+
+				for index := range entities {
+					if err := runHooks(txCtx, c.hooks.afterDelete, &entities[index]); err != nil {
+						return bulkItemError(index, err)
+					}
+				}
+
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+				"deleted": deleted,
+			})
+		},
+	}
+}
+
+func duplicateIds(ids []any) []string {
+	seen := map[string]bool{}
+	duplicates := []string{}
+
+	for _, id := range ids {
+		if id == nil {
+			continue
+		}
+
+		key := fmt.Sprint(id)
+
+		if seen[key] && !slices.Contains(duplicates, key) {
+			duplicates = append(duplicates, key)
+		}
+
+		seen[key] = true
+	}
+
+	return duplicates
+}
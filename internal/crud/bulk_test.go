@@ -0,0 +1,104 @@
+package crud
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type recordingCrud struct {
+	Crud[testWidget]
+	finds int
+}
+
+func (r *recordingCrud) FindAll(ctx context.Context, query *Query, entities *[]testWidget) error {
+	r.finds++
+
+	return nil
+}
+
+func TestBulkRoutesRejectDuplicateIds(t *testing.T) {
+	first := uuid.NewString()
+	second := uuid.NewString()
+	third := uuid.NewString()
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		want   string
+	}{
+		{
+			name:   "patch",
+			method: fiber.MethodPatch,
+			body:   `{"items":[{"id":"` + first + `","count":1},{"id":"` + second + `","count":2},{"id":"` + first + `","count":3}]}`,
+			want:   "duplicate ids are not allowed: " + first,
+		},
+		{
+			name:   "patch with differently cased ids",
+			method: fiber.MethodPatch,
+			body:   `{"items":[{"id":"` + first + `","count":1},{"id":"` + strings.ToUpper(first) + `","count":2}]}`,
+			want:   "duplicate ids are not allowed: " + first,
+		},
+		{
+			name:   "delete",
+			method: fiber.MethodDelete,
+			body:   `{"ids":["` + first + `","` + second + `","` + third + `","` + second + `","` + first + `","` + first + `"]}`,
+			want:   "duplicate ids are not allowed: " + second + ", " + first,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recording := &recordingCrud{}
+			api := newTestApi[testWidget](t, recording)
+
+			request := httptest.NewRequest(test.method, "/testwidgets/bulk", strings.NewReader(test.body))
+			request.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+			response, err := newTestApp(api.BulkPatchRoute(), api.BulkDeleteRoute()).Test(request)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			body, _ := io.ReadAll(response.Body)
+
+			if response.StatusCode != fiber.StatusBadRequest || !strings.Contains(string(body), test.want) {
+				t.Fatalf("expected 400 with %q, got %d: %s", test.want, response.StatusCode, body)
+			}
+
+			if recording.finds != 0 {
+				t.Fatalf("expected the duplicates to be rejected before loading, got %d finds", recording.finds)
+			}
+		})
+	}
+}
+
+func TestDuplicateIds(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  []any
+		want []string
+	}{
+		{name: "none", ids: []any{1, 2, 3}, want: []string{}},
+		{name: "skips unparsed", ids: []any{nil, 1, nil}, want: []string{}},
+		{name: "reports each once", ids: []any{1, 2, 1, 1, 3, 2}, want: []string{"1", "2"}},
+		{name: "strings", ids: []any{"a", "b", "a"}, want: []string{"a"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := duplicateIds(test.ids)
+
+			if strings.Join(got, ",") != strings.Join(test.want, ",") || len(got) != len(test.want) {
+				t.Fatalf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,379 @@
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"github.com/connor-davis/dynamic-crud/internal/cache"
+	"github.com/connor-davis/dynamic-crud/internal/routing"
+	"github.com/connor-davis/dynamic-crud/internal/routing/schemas"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+)
+
+type cachedPage[T any] struct {
+	page     Page
+	entities []T
+}
+
+type cachedCrud[T any] struct {
+	crud       Crud[T]
+	cache      cache.Cache
+	model      *model
+	generation atomic.Uint64
+}
+
+func NewCachedCrud[T any](crud Crud[T], cache cache.Cache) Crud[T] {
+	return &cachedCrud[T]{
+		crud:  crud,
+		cache: cache,
+		model: parseModel[T](),
+	}
+}
+
+func (c *crudApi[T]) AssignCache(cache cache.Cache) CrudApi[T] {
+	c.cache = cache
+	c.crud = NewCachedCrud(c.crud, cache)
+
+	return c
+}
+
+func (c *crudApi[T]) CacheStatsRoute() routing.Route {
+	if c.cache == nil {
+		panic(fmt.Sprintf("cannot report cache statistics for %ss because no cache is assigned", strings.ToLower(c.name)))
+	}
+
+	responses := openapi3.NewResponses()
+
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: &openapi3.Response{
+			Description: openapi3.Ptr(fmt.Sprintf("%s cache statistics retrieved successfully.", c.name)),
+			Content: openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(openapi3.NewObjectSchema().
+						WithProperty("hits", openapi3.NewInt64Schema()).
+						WithProperty("misses", openapi3.NewInt64Schema()).
+						WithProperty("evictions", openapi3.NewInt64Schema()).
+						WithProperty("size", openapi3.NewInt64Schema())),
+			},
+		},
+	})
+
+	responses.Set("401", schemas.NewErrorResponse("Unauthorized"))
+	responses.Set("403", schemas.NewErrorResponse("Forbidden"))
+	responses.Set("500", schemas.NewErrorResponse("Internal Server Error"))
+
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     fmt.Sprintf("Get %s cache statistics", c.name),
+			Description: fmt.Sprintf("This endpoint reports the hit, miss and eviction counters of the %s cache.", strings.ToLower(c.name)),
+			Tags:        []string{fmt.Sprintf("%ss", c.name)},
+			Parameters:  c.tenantParameters(),
+			RequestBody: nil,
+			Responses:   responses,
+			Security:    c.security(OperationCacheStats),
+		},
+		Entity:          c.name,
+		Schemas:         c.schemas(),
+		SecuritySchemes: c.securitySchemes(),
+		Method:          routing.GET,
+		Path:            fmt.Sprintf("/%ss/cache/stats", strings.ToLower(c.name)),
+		Middlewares:     c.middlewares(OperationCacheStats),
+		Handler: func(ctx *fiber.Ctx) error {
+			queryCtx, cancel := c.queryContext(ctx, OperationCacheStats)
+			defer cancel()
+
+			if err := c.authorize(queryCtx, OperationCacheStats, nil); err != nil {
+				return err
+			}
+
+			ctx.Set(fiber.HeaderCacheControl, "no-store")
+
+			return ctx.Status(fiber.StatusOK).JSON(c.cache.Stats())
+		},
+	}
+}
+
+func (c *cachedCrud[T]) Create(ctx context.Context, entity *T) error {
+	defer c.invalidate(ctx)
+
+	return c.crud.Create(ctx, entity)
+}
+
+func (c *cachedCrud[T]) CreateBatch(ctx context.Context, entities *[]T, batchSize int) error {
+	defer c.invalidate(ctx)
+
+	return c.crud.CreateBatch(ctx, entities, batchSize)
+}
+
+func (c *cachedCrud[T]) CreateIfAbsent(ctx context.Context, entity *T, columns []string) (bool, error) {
+	defer c.invalidate(ctx)
+
+	return c.crud.CreateIfAbsent(ctx, entity, columns)
+}
+
+func (c *cachedCrud[T]) Update(ctx context.Context, entityId any, entity *T) error {
+	defer c.invalidate(ctx, entityId)
+
+	return c.crud.Update(ctx, entityId, entity)
+}
+
+func (c *cachedCrud[T]) Patch(ctx context.Context, entityId any, entity *T, columns []string) error {
+	defer c.invalidate(ctx, entityId)
+
+	return c.crud.Patch(ctx, entityId, entity, columns)
+}
+
+func (c *cachedCrud[T]) Delete(ctx context.Context, entityId any, entity *T) error {
+	defer c.invalidate(ctx, entityId)
+
+	return c.crud.Delete(ctx, entityId, entity)
+}
+
+func (c *cachedCrud[T]) HardDelete(ctx context.Context, entityId any, entity *T) error {
+	defer c.invalidate(ctx, entityId)
+
+	return c.crud.HardDelete(ctx, entityId, entity)
+}
+
+func (c *cachedCrud[T]) DeleteBatch(ctx context.Context, entityIds []any) (int64, error) {
+	defer c.invalidate(ctx, entityIds...)
+
+	return c.crud.DeleteBatch(ctx, entityIds)
+}
+
+func (c *cachedCrud[T]) Restore(ctx context.Context, entityId any, entity *T) error {
+	defer c.invalidate(ctx, entityId)
+
+	return c.crud.Restore(ctx, entityId, entity)
+}
+
+func (c *cachedCrud[T]) FindOne(ctx context.Context, entityId any, query *Query, entity *T) error {
+	key, ok := c.key(ctx, "one", entityId, query)
+
+	if !ok {
+		return c.crud.FindOne(ctx, entityId, query, entity)
+	}
+
+	if cached, exists := c.cache.Get(key); exists && c.sameTenant(ctx, cached.(T)) {
+		*entity = deepCopyValue(cached.(T))
+
+		return nil
+	}
+
+	generation := c.generation.Load()
+
+	if err := c.crud.FindOne(ctx, entityId, query, entity); err != nil {
+		return err
+	}
+
+	c.store(key, generation, deepCopyValue(*entity))
+
+	return nil
+}
+
+func (c *cachedCrud[T]) FindAll(ctx context.Context, query *Query, entities *[]T) error {
+	key, ok := c.key(ctx, "all", nil, query)
+
+	if !ok {
+		return c.crud.FindAll(ctx, query, entities)
+	}
+
+	if cached, exists := c.cache.Get(key); exists {
+		*entities = deepCopyValue(cached.([]T))
+
+		return nil
+	}
+
+	generation := c.generation.Load()
+
+	if err := c.crud.FindAll(ctx, query, entities); err != nil {
+		return err
+	}
+
+	c.store(key, generation, deepCopyValue(*entities))
+
+	return nil
+}
+
+func (c *cachedCrud[T]) FindByIds(ctx context.Context, entityIds []any, entities *[]T) error {
+	return c.crud.FindByIds(ctx, entityIds, entities)
+}
+
+func (c *cachedCrud[T]) FindPage(ctx context.Context, query *Query, entities *[]T) (*Page, error) {
+	return c.findPage(ctx, "page", query, entities, c.crud.FindPage)
+}
+
+func (c *cachedCrud[T]) FindTrash(ctx context.Context, query *Query, entities *[]T) (*Page, error) {
+	return c.findPage(ctx, "trash", query, entities, c.crud.FindTrash)
+}
+
+func (c *cachedCrud[T]) findPage(ctx context.Context, kind string, query *Query, entities *[]T, find func(context.Context, *Query, *[]T) (*Page, error)) (*Page, error) {
+	key, ok := c.key(ctx, kind, nil, query)
+
+	if !ok {
+		return find(ctx, query, entities)
+	}
+
+	if cached, exists := c.cache.Get(key); exists {
+		result := cached.(cachedPage[T])
+		page := result.page
+		*entities = deepCopyValue(result.entities)
+
+		return &page, nil
+	}
+
+	generation := c.generation.Load()
+	page, err := find(ctx, query, entities)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.store(key, generation, cachedPage[T]{
+		page:     *page,
+		entities: deepCopyValue(*entities),
+	})
+
+	return page, nil
+}
+
+func (c *cachedCrud[T]) key(ctx context.Context, kind string, entityId any, query *Query) (string, bool) {
+	if _, ok := TransactionFrom(ctx); ok {
+		return "", false
+	}
+
+	tenant := ""
+
+	if c.model.tenantField() != nil {
+		tenantId, ok := TenantFrom(ctx)
+
+		if !ok {
+			return "", false
+		}
+
+		tenant = tenantId.String()
+	}
+
+	if kind == "one" && (query == nil || (len(query.Filters) == 0 && len(query.Scopes) == 0 && len(query.Columns) == 0 && len(query.Expand) == 0 && !query.Unscoped)) {
+		return fmt.Sprintf("%s:one:%v", c.model.schema.Table, entityId), true
+	}
+
+	normalized, err := json.Marshal(query)
+
+	if err != nil {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s:%d:%s:%s:%v:%s", c.model.schema.Table, c.generation.Load(), tenant, kind, entityId, normalized), true
+}
+
+func (c *cachedCrud[T]) sameTenant(ctx context.Context, entity T) bool {
+	cached, ok := c.model.tenantOf(&entity)
+
+	if !ok {
+		return true
+	}
+
+	tenantId, _ := TenantFrom(ctx)
+
+	return cached == tenantId
+}
+
+func (c *cachedCrud[T]) invalidate(ctx context.Context, entityIds ...any) {
+	AfterCommit(ctx, func() {
+		c.generation.Add(1)
+
+		for _, entityId := range entityIds {
+			c.cache.Delete(fmt.Sprintf("%s:one:%v", c.model.schema.Table, entityId))
+		}
+	})
+}
+
+func (c *cachedCrud[T]) store(key string, generation uint64, value any) {
+	if c.generation.Load() == generation {
+		c.cache.Set(key, value)
+	}
+}
+
+func deepCopyValue[V any](value V) V {
+	return copyValue(reflect.ValueOf(&value).Elem(), map[uintptr]reflect.Value{}).Interface().(V)
+}
+
+func copyValue(value reflect.Value, visited map[uintptr]reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return value
+		}
+
+		if copied, exists := visited[value.Pointer()]; exists && copied.Type() == value.Type() {
+			return copied
+		}
+
+		copied := reflect.New(value.Type().Elem())
+		visited[value.Pointer()] = copied
+		copied.Elem().Set(copyValue(value.Elem(), visited))
+
+		return copied
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(copyValue(value.Elem(), visited))
+
+		return copied
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+
+		for index := 0; index < value.Len(); index++ {
+			copied.Index(index).Set(copyValue(value.Index(index), visited))
+		}
+
+		return copied
+	case reflect.Array:
+		copied := reflect.New(value.Type()).Elem()
+
+		for index := 0; index < value.Len(); index++ {
+			copied.Index(index).Set(copyValue(value.Index(index), visited))
+		}
+
+		return copied
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		entries := value.MapRange()
+
+		for entries.Next() {
+			copied.SetMapIndex(entries.Key(), copyValue(entries.Value(), visited))
+		}
+
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+
+		for index := 0; index < value.NumField(); index++ {
+			if copied.Field(index).CanSet() {
+				copied.Field(index).Set(copyValue(value.Field(index), visited))
+			}
+		}
+
+		return copied
+	}
+
+	return value
+}
@@ -0,0 +1,222 @@
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/connor-davis/dynamic-crud/internal/auth"
+	"github.com/connor-davis/dynamic-crud/internal/cache"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type storedCrud struct {
+	Crud[testWidget]
+	stored testWidget
+	reads  int
+}
+
+func (s *storedCrud) FindOne(ctx context.Context, entityId any, query *Query, entity *testWidget) error {
+	s.reads++
+	*entity = s.stored
+
+	return nil
+}
+
+func (s *storedCrud) Update(ctx context.Context, entityId any, entity *testWidget) error {
+	s.stored = *entity
+
+	return nil
+}
+
+func TestCachedCrudInvalidatesAfterCommit(t *testing.T) {
+	id := uuid.New()
+	store := &storedCrud{stored: testWidget{Id: id, Name: "before"}}
+	cached := NewCachedCrud[testWidget](store, cache.NewLRU(10, time.Minute))
+
+	read := func() string {
+		var widget testWidget
+
+		if err := cached.FindOne(context.Background(), id, nil, &widget); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return widget.Name
+	}
+
+	if name := read(); name != "before" {
+		t.Fatalf("expected before, got %q", name)
+	}
+
+	txCtx, hooks := withCommitHooks(context.Background())
+	txCtx = WithTransaction(txCtx, newDryRun(t))
+
+	if err := cached.Update(txCtx, id, &testWidget{Id: id, Name: "after"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if name := read(); name != "before" {
+		t.Fatalf("expected the cached value until the commit, got %q", name)
+	}
+
+	hooks.run()
+
+	if name := read(); name != "after" {
+		t.Fatalf("expected the committed value, got %q", name)
+	}
+}
+
+func TestCachedCrudInvalidatesWithoutTransaction(t *testing.T) {
+	id := uuid.New()
+	store := &storedCrud{stored: testWidget{Id: id, Name: "before"}}
+	cached := NewCachedCrud[testWidget](store, cache.NewLRU(10, time.Minute))
+
+	var widget testWidget
+
+	_ = cached.FindOne(context.Background(), id, nil, &widget)
+	_ = cached.Update(context.Background(), id, &testWidget{Id: id, Name: "after"})
+	_ = cached.FindOne(context.Background(), id, nil, &widget)
+
+	if widget.Name != "after" || store.reads != 2 {
+		t.Fatalf("expected a fresh read of after, got %q after %d reads", widget.Name, store.reads)
+	}
+}
+
+func TestDeepCopyValue(t *testing.T) {
+	type tag struct {
+		Label string
+	}
+
+	type document struct {
+		Title    string
+		Tags     []tag
+		Metadata map[string]any
+		Parent   *tag
+		Created  time.Time
+	}
+
+	original := document{
+		Title:    "original",
+		Tags:     []tag{{Label: "a"}},
+		Metadata: map[string]any{"nested": []any{"x"}},
+		Parent:   &tag{Label: "parent"},
+		Created:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	copied := deepCopyValue(original)
+
+	copied.Title = "changed"
+	copied.Tags[0].Label = "changed"
+	copied.Metadata["nested"].([]any)[0] = "changed"
+	copied.Parent.Label = "changed"
+
+	if original.Title != "original" || original.Tags[0].Label != "a" || original.Metadata["nested"].([]any)[0] != "x" || original.Parent.Label != "parent" {
+		t.Fatalf("expected the original to be untouched, got %+v", original)
+	}
+
+	if !copied.Created.Equal(original.Created) {
+		t.Fatalf("expected times to be copied, got %v", copied.Created)
+	}
+}
+
+func TestCachedCrudReturnsCopies(t *testing.T) {
+	type labelled struct {
+		Id     uuid.UUID `gorm:"type:uuid;primaryKey"`
+		Labels []string  `gorm:"serializer:json"`
+	}
+
+	id := uuid.New()
+	cached := &cachedCrud[labelled]{
+		cache: cache.NewLRU(10, time.Minute),
+		model: parseModel[labelled](),
+	}
+
+	cached.store(fmt.Sprintf("%s:one:%v", cached.model.schema.Table, id), 0, deepCopyValue(labelled{Id: id, Labels: []string{"a"}}))
+
+	var first, second labelled
+
+	_ = cached.FindOne(context.Background(), id, nil, &first)
+	first.Labels[0] = "mutated by a hook"
+	_ = cached.FindOne(context.Background(), id, nil, &second)
+
+	if second.Labels[0] != "a" {
+		t.Fatalf("expected the cached value to be isolated, got %v", second.Labels)
+	}
+}
+
+func TestCacheStatsRoute(t *testing.T) {
+	id := uuid.New()
+	admin := &auth.Claims{Subject: "u2", Raw: map[string]any{"roles": []any{"admin"}}}
+	member := &auth.Claims{Subject: "u1", Raw: map[string]any{}}
+
+	tests := []struct {
+		name       string
+		claims     *auth.Claims
+		wantStatus int
+	}{
+		{name: "anonymous", wantStatus: fiber.StatusUnauthorized},
+		{name: "member", claims: member, wantStatus: fiber.StatusForbidden},
+		{name: "admin", claims: admin, wantStatus: fiber.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := &storedCrud{stored: testWidget{Id: id, Name: "cached"}}
+
+			api := newTestApi[testWidget](t, store)
+			api.AssignCache(cache.NewLRU(10, time.Minute)).
+				AssignAuthentication(claimsAuthenticator{test.claims}, OperationCacheStats).
+				Authorize(OperationCacheStats, func(ctx context.Context, claims *auth.Claims, entity *testWidget) error {
+					if claims.Grants("admin") {
+						return nil
+					}
+
+					return NewError(KindForbidden, "Only administrators can read cache statistics.")
+				})
+
+			for range 3 {
+				if err := api.crud.FindOne(context.Background(), id, nil, &testWidget{}); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			response, err := newTestApp(api.CacheStatsRoute()).Test(httptest.NewRequest(fiber.MethodGet, "/testwidgets/cache/stats", nil))
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if response.StatusCode != test.wantStatus {
+				t.Fatalf("expected status %d, got %d", test.wantStatus, response.StatusCode)
+			}
+
+			if test.wantStatus != fiber.StatusOK {
+				return
+			}
+
+			stats := cache.Stats{}
+
+			if err := json.NewDecoder(response.Body).Decode(&stats); err != nil {
+				t.Fatalf("failed to decode stats: %v", err)
+			}
+
+			if stats.Hits != 2 || stats.Misses != 1 || stats.Size != 1 {
+				t.Fatalf("expected 2 hits, 1 miss and 1 entry, got %+v", stats)
+			}
+		})
+	}
+}
+
+func TestCacheStatsRouteRequiresCache(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic without a cache")
+		}
+	}()
+
+	newTestApi[testWidget](t, nil).CacheStatsRoute()
+}
@@ -0,0 +1,162 @@
+package crud
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+)
+
+var readOperations = []Operation{
+	OperationGetOne,
+	OperationGetAll,
+	OperationTrash,
+}
+
+func (c *crudApi[T]) AssignCacheControl(policy string, operations ...Operation) CrudApi[T] {
+	if len(operations) == 0 {
+		operations = readOperations
+	}
+
+	for _, operation := range operations {
+		c.cacheControl[operation] = policy
+	}
+
+	return c
+}
+
+func (m *model) lastModified(entity any) time.Time {
+	for _, field := range m.schema.Fields {
+		if field.DBName == "" || field.AutoUpdateTime == 0 {
+			continue
+		}
+
+		value, _ := field.ValueOf(context.Background(), reflect.ValueOf(entity).Elem())
+
+		if modified, ok := value.(time.Time); ok {
+			return modified.UTC().Truncate(time.Second)
+		}
+	}
+
+	return time.Time{}
+}
+
+func weakETag(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+func etagMatches(header string, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *crudApi[T]) send(ctx *fiber.Ctx, operation Operation, body []byte, etag string, modified time.Time) error {
+	if policy, exists := c.cacheControl[operation]; exists {
+		ctx.Set(fiber.HeaderCacheControl, policy)
+	}
+
+	if etag == "" {
+		etag = weakETag(body)
+	}
+
+	ctx.Set(fiber.HeaderETag, etag)
+
+	if !modified.IsZero() {
+		ctx.Set(fiber.HeaderLastModified, modified.Format(http.TimeFormat))
+	}
+
+	if c.notModified(ctx, etag, modified) {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
+
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	return ctx.Status(fiber.StatusOK).Send(body)
+}
+
+func (c *crudApi[T]) notModified(ctx *fiber.Ctx, etag string, modified time.Time) bool {
+	if noneMatch := ctx.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		return etagMatches(noneMatch, etag)
+	}
+
+	if modified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(ctx.Get(fiber.HeaderIfModifiedSince))
+
+	return err == nil && !modified.After(since)
+}
+
+func conditionalParameters(lastModified bool) []*openapi3.ParameterRef {
+	parameters := []*openapi3.ParameterRef{
+		{
+			Value: openapi3.NewHeaderParameter("If-None-Match").
+				WithDescription("ETags of a cached response. The response is 304 when one of them is still current.").
+				WithSchema(openapi3.NewStringSchema()),
+		},
+	}
+
+	if lastModified {
+		parameters = append(parameters, &openapi3.ParameterRef{
+			Value: openapi3.NewHeaderParameter("If-Modified-Since").
+				WithDescription("The Last-Modified date of a cached response. Ignored when If-None-Match is sent.").
+				WithSchema(openapi3.NewStringSchema()),
+		})
+	}
+
+	return parameters
+}
+
+func cacheHeaders(lastModified bool) openapi3.Headers {
+	headers := openapi3.Headers{
+		"ETag": etagHeader(),
+		"Cache-Control": &openapi3.HeaderRef{
+			Value: &openapi3.Header{
+				Parameter: openapi3.Parameter{
+					Description: "The caching policy configured for this endpoint.",
+					Schema:      openapi3.NewStringSchema().NewRef(),
+				},
+			},
+		},
+	}
+
+	if lastModified {
+		headers["Last-Modified"] = &openapi3.HeaderRef{
+			Value: &openapi3.Header{
+				Parameter: openapi3.Parameter{
+					Description: "When the returned data was last modified.",
+					Schema:      openapi3.NewStringSchema().NewRef(),
+				},
+			},
+		}
+	}
+
+	return headers
+}
+
+func notModifiedResponse(lastModified bool) *openapi3.ResponseRef {
+	return &openapi3.ResponseRef{
+		Value: &openapi3.Response{
+			Description: openapi3.Ptr("Not Modified"),
+			Headers:     cacheHeaders(lastModified),
+		},
+	}
+}
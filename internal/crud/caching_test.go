@@ -0,0 +1,110 @@
+package crud
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestSendConditional(t *testing.T) {
+	body := []byte(`{"items":[]}`)
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		modified         time.Time
+		headers          map[string]string
+		wantStatus       int
+		wantLastModified bool
+	}{
+		{
+			name:       "collection without validators",
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:       "collection ignores If-Modified-Since",
+			headers:    map[string]string{fiber.HeaderIfModifiedSince: modified.Add(time.Hour).Format(http.TimeFormat)},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:       "collection matches its body ETag",
+			headers:    map[string]string{fiber.HeaderIfNoneMatch: weakETag(body)},
+			wantStatus: fiber.StatusNotModified,
+		},
+		{
+			name:             "entity honours If-Modified-Since",
+			modified:         modified,
+			headers:          map[string]string{fiber.HeaderIfModifiedSince: modified.Format(http.TimeFormat)},
+			wantStatus:       fiber.StatusNotModified,
+			wantLastModified: true,
+		},
+		{
+			name:             "entity modified since",
+			modified:         modified,
+			headers:          map[string]string{fiber.HeaderIfModifiedSince: modified.Add(-time.Hour).Format(http.TimeFormat)},
+			wantStatus:       fiber.StatusOK,
+			wantLastModified: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api := &crudApi[testWidget]{cacheControl: map[Operation]string{}}
+			ctx := newRequestCtx(t, "/widgets")
+
+			for name, value := range test.headers {
+				ctx.Request().Header.Set(name, value)
+			}
+
+			if err := api.send(ctx, OperationGetAll, body, "", test.modified); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if status := ctx.Response().StatusCode(); status != test.wantStatus {
+				t.Fatalf("expected status %d, got %d", test.wantStatus, status)
+			}
+
+			if lastModified := string(ctx.Response().Header.Peek(fiber.HeaderLastModified)); (lastModified != "") != test.wantLastModified {
+				t.Fatalf("unexpected Last-Modified header %q", lastModified)
+			}
+		})
+	}
+}
+
+func TestRespondCacheControl(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation Operation
+		prefer    string
+		want      string
+	}{
+		{name: "configured write operation", operation: OperationCreate, want: "no-store"},
+		{name: "configured with minimal return", operation: OperationCreate, prefer: "return=minimal", want: "no-store"},
+		{name: "unconfigured write operation", operation: OperationPatch},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api := &crudApi[testWidget]{
+				model:        parseModel[testWidget](),
+				cacheControl: map[Operation]string{OperationCreate: "no-store"},
+			}
+
+			ctx := newRequestCtx(t, "/widgets")
+
+			if test.prefer != "" {
+				ctx.Request().Header.Set("Prefer", test.prefer)
+			}
+
+			if err := api.respond(ctx, test.operation, fiber.StatusCreated, &testWidget{Name: "widget"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if cacheControl := string(ctx.Response().Header.Peek(fiber.HeaderCacheControl)); cacheControl != test.want {
+				t.Fatalf("expected Cache-Control %q, got %q", test.want, cacheControl)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package crud
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const disconnectInterval = 100 * time.Millisecond
+
+func cancellation() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		requestCtx, cancel := context.WithCancel(ctx.UserContext())
+		done := make(chan struct{})
+
+		defer cancel()
+		defer close(done)
+
+		ctx.SetUserContext(requestCtx)
+
+		go watchDisconnect(ctx.Context().Conn(), cancel, done)
+
+		return ctx.Next()
+	}
+}
+
+func watchDisconnect(conn net.Conn, cancel context.CancelFunc, done <-chan struct{}) {
+	ticker := time.NewTicker(disconnectInterval)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if disconnected(conn) {
+				cancel()
+
+				return
+			}
+		}
+	}
+}
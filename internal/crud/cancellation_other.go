@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package crud
+
+import "net"
+
+func disconnected(conn net.Conn) bool {
+	return false
+}
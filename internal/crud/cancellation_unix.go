@@ -0,0 +1,45 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package crud
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+func disconnected(conn net.Conn) bool {
+	syscallConn, ok := conn.(syscall.Conn)
+
+	if !ok {
+		return false
+	}
+
+	raw, err := syscallConn.SyscallConn()
+
+	if err != nil {
+		return false
+	}
+
+	closed := false
+	buffer := make([]byte, 1)
+
+	if err := raw.Read(func(fd uintptr) bool {
+		n, _, err := syscall.Recvfrom(int(fd), buffer, syscall.MSG_PEEK|syscall.MSG_DONTWAIT)
+
+		switch {
+		case err == nil:
+			closed = n == 0
+		case errors.Is(err, syscall.EAGAIN), errors.Is(err, syscall.EWOULDBLOCK), errors.Is(err, syscall.EINTR):
+			closed = false
+		default:
+			closed = true
+		}
+
+		return true
+	}); err != nil {
+		return true
+	}
+
+	return closed
+}
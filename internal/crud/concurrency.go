@@ -0,0 +1,247 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/connor-davis/dynamic-crud/internal/routing/schemas"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const IfMatchProperty = "ifMatch"
+
+type versionsKey struct{}
+
+func WithExpectedVersions(ctx context.Context, versions ...any) context.Context {
+	return context.WithValue(ctx, versionsKey{}, versions)
+}
+
+func ExpectedVersionsFrom(ctx context.Context) ([]any, bool) {
+	versions, ok := ctx.Value(versionsKey{}).([]any)
+
+	return versions, ok
+}
+
+func (m *model) versionField() *schema.Field {
+	for _, field := range m.schema.Fields {
+		if field.DBName != "" && schemas.IsVersion(field.StructField) {
+			return field
+		}
+	}
+
+	return nil
+}
+
+func (m *model) concurrencyField() *schema.Field {
+	if field := m.versionField(); field != nil {
+		return field
+	}
+
+	for _, field := range m.schema.Fields {
+		if field.DBName != "" && field.AutoUpdateTime > 0 {
+			return field
+		}
+	}
+
+	return nil
+}
+
+func (m *model) etag(entity any) string {
+	field := m.concurrencyField()
+
+	if field == nil {
+		return ""
+	}
+
+	value, zero := field.ValueOf(context.Background(), reflect.ValueOf(entity).Elem())
+
+	if zero {
+		return ""
+	}
+
+	switch value := value.(type) {
+	case time.Time:
+		return strconv.Quote(strconv.FormatInt(value.UnixMicro(), 10))
+	default:
+		return strconv.Quote(fmt.Sprint(value))
+	}
+}
+
+func (m *model) parseETag(tag string) (any, bool) {
+	field := m.concurrencyField()
+
+	if field == nil || strings.HasPrefix(tag, "W/") || len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		return nil, false
+	}
+
+	value, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+
+	if err != nil {
+		return nil, false
+	}
+
+	if field.FieldType == reflect.TypeOf(time.Time{}) {
+		return time.UnixMicro(value).UTC(), true
+	}
+
+	return value, true
+}
+
+func (c *crud[T]) expectVersion(ctx context.Context, db *gorm.DB) *gorm.DB {
+	versions, ok := ExpectedVersionsFrom(ctx)
+	field := c.model.concurrencyField()
+
+	if !ok || field == nil {
+		return db
+	}
+
+	return db.Where(clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Values: versions})
+}
+
+func (c *crud[T]) versionedValues(entity *T, columns []string) map[string]any {
+	values := map[string]any{}
+	value := reflect.ValueOf(entity).Elem()
+
+	for _, column := range columns {
+		if field, exists := c.model.schema.FieldsByDBName[column]; exists {
+			values[column], _ = field.ValueOf(context.Background(), value)
+		}
+	}
+
+	if field := c.model.versionField(); field != nil {
+		values[field.DBName] = gorm.Expr("? + 1", clause.Column{Name: field.DBName})
+	}
+
+	return values
+}
+
+func (c *crud[T]) matched(ctx context.Context, entityId any, result *gorm.DB, unscoped bool) error {
+	err := c.affected(entityId, result)
+
+	if _, ok := ExpectedVersionsFrom(ctx); !ok || !IsNotFound(err) {
+		return err
+	}
+
+	db := c.database(ctx)
+
+	if unscoped {
+		db = db.Unscoped()
+	}
+
+	var count int64
+
+	if err := db.Model(new(T)).Where("id = ?", entityId).Count(&count).Error; err != nil {
+		return c.translate(err)
+	}
+
+	if count == 0 {
+		return err
+	}
+
+	return &Error{
+		Kind:   KindPreconditionFailed,
+		Detail: fmt.Sprintf("The %s has been modified since it was retrieved.", strings.ToLower(c.model.schema.Name)),
+	}
+}
+
+func (c *crudApi[T]) AssignRequirePreconditions(required bool) CrudApi[T] {
+	c.requirePreconditions = required
+
+	return c
+}
+
+func (c *crudApi[T]) precondition(ctx *fiber.Ctx, queryCtx context.Context) (context.Context, error) {
+	versions, err := c.expectedVersions(strings.TrimSpace(ctx.Get(fiber.HeaderIfMatch)), "The If-Match header")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return withVersions(queryCtx, versions), nil
+}
+
+func (c *crudApi[T]) expectedVersions(ifMatch string, source string) ([]any, error) {
+	if c.model.concurrencyField() == nil {
+		return nil, nil
+	}
+
+	if ifMatch == "" {
+		if c.requirePreconditions {
+			return nil, NewError(KindPreconditionRequired, fmt.Sprintf("%s is required to modify a %s.", source, strings.ToLower(c.name)))
+		}
+
+		return nil, nil
+	}
+
+	if ifMatch == "*" {
+		return nil, nil
+	}
+
+	versions := []any{}
+
+	for _, tag := range strings.Split(ifMatch, ",") {
+		if version, ok := c.model.parseETag(strings.TrimSpace(tag)); ok {
+			versions = append(versions, version)
+		}
+	}
+
+	if len(versions) == 0 {
+		return nil, NewError(KindPreconditionFailed, fmt.Sprintf("%s does not match the current %s.", source, strings.ToLower(c.name)))
+	}
+
+	return versions, nil
+}
+
+func withVersions(ctx context.Context, versions []any) context.Context {
+	if versions == nil {
+		return ctx
+	}
+
+	return WithExpectedVersions(ctx, versions...)
+}
+
+func (c *crudApi[T]) checkVersion(entity *T, versions []any) error {
+	if versions == nil {
+		return nil
+	}
+
+	if current, ok := c.model.parseETag(c.model.etag(entity)); ok && slices.Contains(versions, current) {
+		return nil
+	}
+
+	return NewError(KindPreconditionFailed, fmt.Sprintf("The %s has been modified since it was retrieved.", strings.ToLower(c.name)))
+}
+
+func (c *crudApi[T]) setETag(ctx *fiber.Ctx, entity *T) {
+	if etag := c.model.etag(entity); etag != "" {
+		ctx.Set(fiber.HeaderETag, etag)
+	}
+}
+
+func ifMatchParameter() *openapi3.ParameterRef {
+	return &openapi3.ParameterRef{
+		Value: openapi3.NewHeaderParameter("If-Match").
+			WithDescription("The ETag of the entity being modified. The request fails with 412 when the entity has changed since.").
+			WithSchema(openapi3.NewStringSchema()),
+	}
+}
+
+func etagHeader() *openapi3.HeaderRef {
+	return &openapi3.HeaderRef{
+		Value: &openapi3.Header{
+			Parameter: openapi3.Parameter{
+				Description: "The current version of the returned data. Strong ETags can be sent back in If-Match.",
+				Schema:      openapi3.NewStringSchema().NewRef(),
+			},
+		},
+	}
+}
@@ -0,0 +1,106 @@
+package crud
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type testRevision struct {
+	Id        uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
+	Slug      string         `json:"slug" gorm:"type:text;unique"`
+	Title     string         `json:"title"`
+	Version   int64          `json:"version" crud:"version"`
+	DeletedAt gorm.DeletedAt `json:"deletedAt"`
+}
+
+func TestExpectedVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		ifMatch  string
+		required bool
+		want     []any
+		wantKind Kind
+	}{
+		{name: "no precondition", ifMatch: ""},
+		{name: "missing required precondition", ifMatch: "", required: true, wantKind: KindPreconditionRequired},
+		{name: "wildcard", ifMatch: "*", required: true},
+		{name: "single etag", ifMatch: `"3"`, want: []any{int64(3)}},
+		{name: "several etags", ifMatch: `"3", "4"`, want: []any{int64(3), int64(4)}},
+		{name: "weak etag", ifMatch: `W/"3"`, wantKind: KindPreconditionFailed},
+		{name: "unquoted etag", ifMatch: `3`, wantKind: KindPreconditionFailed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api := &crudApi[testRevision]{
+				name:                 "Revision",
+				model:                parseModel[testRevision](),
+				requirePreconditions: test.required,
+			}
+
+			versions, err := api.expectedVersions(test.ifMatch, "The If-Match header")
+
+			if test.wantKind != "" {
+				var crudError *Error
+
+				if !errors.As(err, &crudError) || crudError.Kind != test.wantKind {
+					t.Fatalf("expected a %s error, got %v", test.wantKind, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(versions, test.want) {
+				t.Fatalf("expected %v, got %v", test.want, versions)
+			}
+		})
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	api := &crudApi[testRevision]{
+		name:  "Revision",
+		model: parseModel[testRevision](),
+	}
+
+	entity := &testRevision{Id: uuid.New(), Version: 4}
+
+	tests := []struct {
+		name     string
+		versions []any
+		wantErr  bool
+	}{
+		{name: "no precondition", versions: nil},
+		{name: "current version", versions: []any{int64(4)}},
+		{name: "one of several versions", versions: []any{int64(3), int64(4)}},
+		{name: "stale version", versions: []any{int64(3)}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := api.checkVersion(entity, test.versions)
+
+			if test.wantErr {
+				var crudError *Error
+
+				if !errors.As(err, &crudError) || crudError.Kind != KindPreconditionFailed {
+					t.Fatalf("expected a precondition failure, got %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
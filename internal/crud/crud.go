@@ -1,41 +1,226 @@
 package crud
 
-import "github.com/connor-davis/dynamic-crud/internal/storage"
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/connor-davis/dynamic-crud/internal/storage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
 
 type Crud[T any] interface {
-	Create(entity *T) error
-	Update(entityId any, entity *T) error
-	Delete(entityId any, entity *T) error
-	FindOne(entityId any, entity *T) error
-	FindAll(entities *[]T) error
+	Create(ctx context.Context, entity *T) error
+	CreateBatch(ctx context.Context, entities *[]T, batchSize int) error
+	CreateIfAbsent(ctx context.Context, entity *T, columns []string) (bool, error)
+	Update(ctx context.Context, entityId any, entity *T) error
+	Patch(ctx context.Context, entityId any, entity *T, columns []string) error
+	Delete(ctx context.Context, entityId any, entity *T) error
+	HardDelete(ctx context.Context, entityId any, entity *T) error
+	DeleteBatch(ctx context.Context, entityIds []any) (int64, error)
+	Restore(ctx context.Context, entityId any, entity *T) error
+	FindOne(ctx context.Context, entityId any, query *Query, entity *T) error
+	FindAll(ctx context.Context, query *Query, entities *[]T) error
+	FindByIds(ctx context.Context, entityIds []any, entities *[]T) error
+	FindPage(ctx context.Context, query *Query, entities *[]T) (*Page, error)
+	FindTrash(ctx context.Context, query *Query, entities *[]T) (*Page, error)
 }
 
 type crud[T any] struct {
 	storage storage.Storage
+	model   *model
 }
 
 func NewCrud[T any](storage storage.Storage) Crud[T] {
 	return &crud[T]{
 		storage: storage,
+		model:   parseModel[T](),
+	}
+}
+
+func (c *crud[T]) Create(ctx context.Context, entity *T) error {
+	if err := c.stampTenant(ctx, entity); err != nil {
+		return err
+	}
+
+	return c.translate(c.database(ctx).Create(entity).Error)
+}
+
+func (c *crud[T]) CreateBatch(ctx context.Context, entities *[]T, batchSize int) error {
+	if len(*entities) == 0 {
+		return nil
+	}
+
+	for index := range *entities {
+		if err := c.stampTenant(ctx, &(*entities)[index]); err != nil {
+			return err
+		}
+	}
+
+	return c.translate(c.database(ctx).CreateInBatches(entities, batchSize).Error)
+}
+
+func (c *crud[T]) Update(ctx context.Context, entityId any, entity *T) error {
+	db := c.expectVersion(ctx, c.database(ctx).Model(entity).Where("id = ?", entityId))
+
+	if c.model.versionField() != nil {
+		return c.matched(ctx, entityId, db.Updates(c.versionedValues(entity, c.model.writableColumns())), false)
+	}
+
+	return c.matched(ctx, entityId, db.
+		Select("*").
+		Omit(append(c.model.readOnlyColumns(), clause.Associations)...).
+		Updates(entity), false)
+}
+
+func (c *crud[T]) Patch(ctx context.Context, entityId any, entity *T, columns []string) error {
+	if len(columns) == 0 {
+		return nil
+	}
+
+	db := c.expectVersion(ctx, c.database(ctx).Model(entity).Where("id = ?", entityId))
+
+	if c.model.versionField() != nil {
+		return c.matched(ctx, entityId, db.Updates(c.versionedValues(entity, columns)), false)
+	}
+
+	return c.matched(ctx, entityId, db.
+		Select(append(columns, c.model.autoUpdateColumns()...)).
+		Updates(entity), false)
+}
+
+func (c *crud[T]) Delete(ctx context.Context, entityId any, entity *T) error {
+	return c.matched(ctx, entityId, c.expectVersion(ctx, c.database(ctx).Where("id = ?", entityId)).Delete(entity), false)
+}
+
+func (c *crud[T]) HardDelete(ctx context.Context, entityId any, entity *T) error {
+	return c.matched(ctx, entityId, c.expectVersion(ctx, c.database(ctx).Unscoped().Where("id = ?", entityId)).Delete(entity), true)
+}
+
+func (c *crud[T]) DeleteBatch(ctx context.Context, entityIds []any) (int64, error) {
+	if len(entityIds) == 0 {
+		return 0, nil
+	}
+
+	result := c.database(ctx).Where("id IN ?", entityIds).Delete(new(T))
+
+	return result.RowsAffected, c.translate(result.Error)
+}
+
+func (c *crud[T]) Restore(ctx context.Context, entityId any, entity *T) error {
+	column := c.model.deletedAtColumn()
+
+	if column == "" {
+		return fmt.Errorf("%s does not support soft deletes", c.model.schema.Name)
 	}
+
+	return c.affected(entityId, c.database(ctx).
+		Unscoped().
+		Model(entity).
+		Where("id = ?", entityId).
+		Where(clause.Neq{Column: clause.Column{Name: column}, Value: nil}).
+		Update(column, nil))
 }
 
-func (c *crud[T]) Create(entity *T) error {
-	return c.storage.Database().Create(entity).Error
+func (c *crud[T]) FindOne(ctx context.Context, entityId any, query *Query, entity *T) error {
+	return c.notFound(entityId, query.ApplySelection(query.Apply(c.database(ctx))).First(entity, "id = ?", entityId).Error)
 }
 
-func (c *crud[T]) Update(entityId any, entity *T) error {
-	return c.storage.Database().Model(entity).Where("id = ?", entityId).Updates(entity).Error
+func (c *crud[T]) FindAll(ctx context.Context, query *Query, entities *[]T) error {
+	return c.translate(query.ApplySelection(query.Apply(c.database(ctx))).Find(entities).Error)
 }
 
-func (c *crud[T]) Delete(entityId any, entity *T) error {
-	return c.storage.Database().Where("id = ?", entityId).Delete(entity).Error
+func (c *crud[T]) FindByIds(ctx context.Context, entityIds []any, entities *[]T) error {
+	if len(entityIds) == 0 {
+		return nil
+	}
+
+	return c.translate(c.database(ctx).Where("id IN ?", entityIds).Find(entities).Error)
 }
 
-func (c *crud[T]) FindOne(entityId any, entity *T) error {
-	return c.storage.Database().First(entity, "id = ?", entityId).Error
+func (c *crud[T]) FindPage(ctx context.Context, query *Query, entities *[]T) (*Page, error) {
+	return c.findPage(c.database(ctx), query, entities)
+}
+
+func (c *crud[T]) FindTrash(ctx context.Context, query *Query, entities *[]T) (*Page, error) {
+	column := c.model.deletedAtColumn()
+
+	if column == "" {
+		return nil, fmt.Errorf("%s does not support soft deletes", c.model.schema.Name)
+	}
+
+	return c.findPage(
+		c.database(ctx).Unscoped().Where(clause.Neq{Column: clause.Column{Name: column}, Value: nil}),
+		query,
+		entities,
+	)
 }
 
-func (c *crud[T]) FindAll(entities *[]T) error {
-	return c.storage.Database().Find(&entities).Error
+func (c *crud[T]) findPage(db *gorm.DB, query *Query, entities *[]T) (*Page, error) {
+	if query == nil {
+		query = &Query{}
+	}
+
+	if query.Limit < 1 {
+		query.Limit = DefaultPageSize
+	}
+
+	page := &Page{}
+
+	if err := query.Apply(db.Session(&gorm.Session{}).Model(new(T))).Count(&page.Total).Error; err != nil {
+		return nil, c.translate(err)
+	}
+
+	if err := query.ApplyPage(query.ApplySelection(query.Apply(db.Session(&gorm.Session{})))).Find(entities).Error; err != nil {
+		return nil, c.translate(err)
+	}
+
+	if len(*entities) > query.Limit {
+		*entities = (*entities)[:query.Limit]
+
+		page.HasMore = true
+
+		if !isDefaultSort(query.Sort) {
+			return page, nil
+		}
+
+		cursor, err := c.cursorOf(&(*entities)[query.Limit-1])
+
+		if err != nil {
+			return nil, err
+		}
+
+		page.NextCursor = cursor
+	}
+
+	return page, nil
+}
+
+func (c *crud[T]) cursorOf(entity *T) (*Cursor, error) {
+	createdAtField, createdAtExists := c.model.schema.FieldsByDBName["created_at"]
+	idField, idExists := c.model.schema.FieldsByDBName["id"]
+
+	if !createdAtExists || !idExists {
+		return nil, fmt.Errorf("cursor pagination requires id and created_at columns")
+	}
+
+	value := reflect.ValueOf(entity).Elem()
+
+	createdAt, _ := createdAtField.ValueOf(context.Background(), value)
+	id, _ := idField.ValueOf(context.Background(), value)
+
+	createdAtValue, createdAtOk := createdAt.(time.Time)
+	idValue, idOk := id.(uuid.UUID)
+
+	if !createdAtOk || !idOk {
+		return nil, fmt.Errorf("cursor pagination requires a uuid id and a time created_at")
+	}
+
+	return &Cursor{
+		CreatedAt: createdAtValue,
+		Id:        idValue,
+	}, nil
 }
@@ -1,35 +1,93 @@
 package crud
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"mime"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/connor-davis/dynamic-crud/common"
+	"github.com/connor-davis/dynamic-crud/internal/cache"
 	"github.com/connor-davis/dynamic-crud/internal/routing"
 	"github.com/connor-davis/dynamic-crud/internal/routing/schemas"
 	"github.com/connor-davis/dynamic-crud/internal/storage"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gofiber/fiber/v2"
-	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 type CrudApi[T any] interface {
 	AssignCreateSchema(schema *openapi3.Schema) CrudApi[T]
 	AssignUpdateSchema(schema *openapi3.Schema) CrudApi[T]
+	AssignMaxPageSize(size int) CrudApi[T]
+	AssignMaxBatchSize(size int) CrudApi[T]
+	AssignSortableFields(fields ...string) CrudApi[T]
+	AssignDefaultSort(sort string) CrudApi[T]
+	AssignWriteMode(mode WriteMode) CrudApi[T]
+	AssignHardDeleteGuard(guard func(ctx *fiber.Ctx) bool) CrudApi[T]
+	AssignQueryTimeout(timeout time.Duration, operations ...Operation) CrudApi[T]
+	AssignRequirePreconditions(required bool) CrudApi[T]
+	AssignCacheControl(policy string, operations ...Operation) CrudApi[T]
+	AssignCache(cache cache.Cache) CrudApi[T]
+	AssignAuthentication(authenticator routing.Authenticator, operations ...Operation) CrudApi[T]
+	AssignScope(scope Scope) CrudApi[T]
+	AssignOwner(owner Owner[T]) CrudApi[T]
+	AssignReadRoles(field string, roles ...string) CrudApi[T]
+	AssignWriteRoles(field string, roles ...string) CrudApi[T]
+	Authorize(operation Operation, policy Policy[T]) CrudApi[T]
+	BeforeCreate(hook Hook[T]) CrudApi[T]
+	AfterCreate(hook Hook[T]) CrudApi[T]
+	BeforeUpdate(hook Hook[T]) CrudApi[T]
+	AfterUpdate(hook Hook[T]) CrudApi[T]
+	BeforeDelete(hook Hook[T]) CrudApi[T]
+	AfterDelete(hook Hook[T]) CrudApi[T]
+	AfterFind(hook Hook[T]) CrudApi[T]
 	CreateRoute() routing.Route
+	BulkCreateRoute() routing.Route
+	UpsertRoute() routing.Route
 	UpdateRoute() routing.Route
+	PatchRoute() routing.Route
+	BulkPatchRoute() routing.Route
 	DeleteRoute() routing.Route
+	BulkDeleteRoute() routing.Route
+	RestoreRoute() routing.Route
 	GetOneRoute() routing.Route
 	GetAllRoute() routing.Route
+	TrashRoute() routing.Route
+	CacheStatsRoute() routing.Route
 }
 
 type crudApi[T any] struct {
-	storage storage.Storage
-	name    string
-	crud    Crud[T]
-	create  *openapi3.Schema
-	update  *openapi3.Schema
+	storage              storage.Storage
+	name                 string
+	model                *model
+	crud                 Crud[T]
+	cache                cache.Cache
+	entity               *openapi3.Schema
+	create               *openapi3.Schema
+	update               *openapi3.Schema
+	maxPageSize          int
+	maxBatchSize         int
+	sortable             []string
+	defaultSort          []Sort
+	writeMode            WriteMode
+	hardDelete           func(ctx *fiber.Ctx) bool
+	requirePreconditions bool
+	cacheControl         map[Operation]string
+	authenticators       map[Operation][]routing.Authenticator
+	policies             map[Operation][]Policy[T]
+	scopes               []Scope
+	owner                Owner[T]
+	readRoles            map[string][]string
+	writeRoles           map[string][]string
+	relationRoles        bool
+	timeout              time.Duration
+	timeouts             map[Operation]time.Duration
+	hooks                hooks[T]
 }
 
 type UpdateParams struct {
@@ -40,6 +98,10 @@ type DeleteParams struct {
 	Id string `json:"id"`
 }
 
+type RestoreParams struct {
+	Id string `json:"id"`
+}
+
 type GetOneParams struct {
 	Id string `json:"id"`
 }
@@ -52,10 +114,38 @@ func NewCrudApi[T any](storage storage.Storage) CrudApi[T] {
 
 	log.Printf("Initialized CRUD API for %s at /%ss", tReflectionName, strings.ToLower(tReflectionName))
 
+	maxPageSize, err := strconv.Atoi(common.EnvString("APP_MAX_PAGE_SIZE", strconv.Itoa(DefaultMaxPageSize)))
+
+	if err != nil || maxPageSize < 1 {
+		maxPageSize = DefaultMaxPageSize
+	}
+
+	maxBatchSize, err := strconv.Atoi(common.EnvString("APP_MAX_BATCH_SIZE", strconv.Itoa(DefaultMaxBatchSize)))
+
+	if err != nil || maxBatchSize < 1 {
+		maxBatchSize = DefaultMaxBatchSize
+	}
+
+	model := parseModel[T]()
+
 	return &crudApi[T]{
-		storage: storage,
-		name:    tReflectionName,
-		crud:    crud,
+		storage:        storage,
+		name:           tReflectionName,
+		model:          model,
+		crud:           crud,
+		entity:         schemas.NewEntitySchema(tReflection.Elem()),
+		create:         schemas.NewCreateSchema(tReflection.Elem()),
+		update:         schemas.NewUpdateSchema(tReflection.Elem()),
+		maxPageSize:    maxPageSize,
+		maxBatchSize:   maxBatchSize,
+		timeout:        parseTimeout(common.EnvString("APP_QUERY_TIMEOUT", "0s")),
+		timeouts:       map[Operation]time.Duration{},
+		cacheControl:   map[Operation]string{},
+		authenticators: map[Operation][]routing.Authenticator{},
+		policies:       map[Operation][]Policy[T]{},
+		readRoles:      tagRoles(model.schema, schemas.ReadRoles),
+		writeRoles:     tagRoles(model.schema, schemas.WriteRoles),
+		relationRoles:  hasRelationRoles(model.schema, map[*schema.Schema]bool{}),
 	}
 }
 
@@ -71,96 +161,267 @@ func (c *crudApi[T]) AssignUpdateSchema(schema *openapi3.Schema) CrudApi[T] {
 	return c
 }
 
-func (c *crudApi[T]) CreateRoute() routing.Route {
-	responses := openapi3.NewResponses()
+func (c *crudApi[T]) AssignMaxPageSize(size int) CrudApi[T] {
+	if size > 0 {
+		c.maxPageSize = size
+	}
 
-	responses.Set("200", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithDescription(fmt.Sprintf("%s created successfully.", c.name)).
-			WithContent(openapi3.Content{
-				"text/plain": openapi3.NewMediaType().
-					WithSchema(openapi3.NewStringSchema().WithDefault("OK")),
-			}),
-	})
+	return c
+}
 
-	responses.Set("400", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Bad Request").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
-	})
+func (c *crudApi[T]) AssignMaxBatchSize(size int) CrudApi[T] {
+	if size > 0 {
+		c.maxBatchSize = size
+	}
 
-	responses.Set("401", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Unauthorized").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
-	})
+	return c
+}
 
-	responses.Set("403", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Forbidden").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
-	})
+func (c *crudApi[T]) AssignSortableFields(fields ...string) CrudApi[T] {
+	for _, field := range fields {
+		if _, exists := c.model.field(field); !exists {
+			panic(fmt.Sprintf("cannot sort %s by unknown field %q", c.name, field))
+		}
+	}
 
-	responses.Set("500", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Internal Server Error").
-			WithContent(openapi3.Content{
+	c.sortable = fields
+
+	return c
+}
+
+func (c *crudApi[T]) AssignDefaultSort(sort string) CrudApi[T] {
+	sorts, err := c.model.parseSort(sort, c.model.order)
+
+	if err != nil {
+		panic(fmt.Sprintf("invalid default sort for %s: %s", c.name, err.Error()))
+	}
+
+	c.defaultSort = sorts
+
+	return c
+}
+
+func (c *crudApi[T]) AssignWriteMode(mode WriteMode) CrudApi[T] {
+	c.writeMode = mode
+
+	return c
+}
+
+func (c *crudApi[T]) AssignHardDeleteGuard(guard func(ctx *fiber.Ctx) bool) CrudApi[T] {
+	c.hardDelete = guard
+
+	return c
+}
+
+func (c *crudApi[T]) AssignQueryTimeout(timeout time.Duration, operations ...Operation) CrudApi[T] {
+	for _, operation := range operationsOrAll(operations) {
+		c.timeouts[operation] = timeout
+	}
+
+	return c
+}
+
+func (c *crudApi[T]) entityRef() *openapi3.SchemaRef {
+	return schemas.Ref(c.name, c.entity)
+}
+
+func (c *crudApi[T]) itemRef() *openapi3.SchemaRef {
+	return schemas.Ref(fmt.Sprintf("%sItem", c.name), schemas.NewItemSchema(c.entityRef()))
+}
+
+func (c *crudApi[T]) listRef() *openapi3.SchemaRef {
+	return schemas.Ref(fmt.Sprintf("%sList", c.name), schemas.NewListSchema(c.entityRef()))
+}
+
+func (c *crudApi[T]) createRef() *openapi3.SchemaRef {
+	return schemas.Ref(fmt.Sprintf("Create%s", c.name), c.create)
+}
+
+func (c *crudApi[T]) updateRef() *openapi3.SchemaRef {
+	return schemas.Ref(fmt.Sprintf("Update%s", c.name), c.update)
+}
+
+func (c *crudApi[T]) patchRef() *openapi3.SchemaRef {
+	return schemas.Ref(fmt.Sprintf("Patch%s", c.name), schemas.NewPatchSchema(c.update))
+}
+
+func (c *crudApi[T]) upsertRef() *openapi3.SchemaRef {
+	return schemas.Ref(fmt.Sprintf("%sUpsert", c.name), schemas.NewUpsertSchema(c.entityRef()))
+}
+
+func (c *crudApi[T]) bulkRef() *openapi3.SchemaRef {
+	return schemas.Ref(fmt.Sprintf("%sBulk", c.name), schemas.NewBulkSchema(c.entityRef(), c.maxBatchSize))
+}
+
+func (c *crudApi[T]) bulkResultRef() *openapi3.SchemaRef {
+	return schemas.Ref(fmt.Sprintf("%sBulkResult", c.name), schemas.NewBulkResultSchema(c.entityRef()))
+}
+
+func (c *crudApi[T]) bulkCreateRef() *openapi3.SchemaRef {
+	return schemas.Ref(fmt.Sprintf("BulkCreate%s", c.name), schemas.NewBulkSchema(c.createRef(), c.maxBatchSize))
+}
+
+func (c *crudApi[T]) bulkPatchItemRef() *openapi3.SchemaRef {
+	item := schemas.NewBulkPatchItemSchema(schemas.NewPatchSchema(c.update))
+
+	if c.model.concurrencyField() != nil {
+		ifMatch := openapi3.NewStringSchema()
+		ifMatch.Description = fmt.Sprintf("The ETag of the %s being patched. The item fails with 412 when the %s has changed since.", strings.ToLower(c.name), strings.ToLower(c.name))
+
+		item.Properties[IfMatchProperty] = ifMatch.NewRef()
+
+		if c.requirePreconditions {
+			item.Required = append(item.Required, IfMatchProperty)
+		}
+	}
+
+	return schemas.Ref(fmt.Sprintf("BulkPatch%sItem", c.name), item)
+}
+
+func (c *crudApi[T]) bulkPatchRef() *openapi3.SchemaRef {
+	return schemas.Ref(fmt.Sprintf("BulkPatch%s", c.name), schemas.NewBulkSchema(c.bulkPatchItemRef(), c.maxBatchSize))
+}
+
+func (c *crudApi[T]) bulkDeleteRef() *openapi3.SchemaRef {
+	ids := schemas.NewBulkIdsSchema(c.maxBatchSize)
+
+	if c.model.concurrencyField() != nil {
+		ifMatch := openapi3.NewObjectSchema().WithAdditionalProperties(openapi3.NewStringSchema())
+		ifMatch.Description = fmt.Sprintf("The ETag of each %s being deleted, keyed by id. An item fails with 412 when the %s has changed since.", strings.ToLower(c.name), strings.ToLower(c.name))
+
+		ids.Properties[IfMatchProperty] = ifMatch.NewRef()
+	}
+
+	return schemas.Ref(fmt.Sprintf("BulkDelete%s", c.name), ids)
+}
+
+func (c *crudApi[T]) schemas() openapi3.Schemas {
+	c.describeAccess()
+
+	components := openapi3.Schemas{}
+
+	for _, ref := range []*openapi3.SchemaRef{
+		c.entityRef(),
+		c.itemRef(),
+		c.listRef(),
+		c.createRef(),
+		c.updateRef(),
+		c.patchRef(),
+		schemas.Ref("JSONPatch", schemas.JSONPatchSchema),
+		c.upsertRef(),
+		c.bulkRef(),
+		c.bulkResultRef(),
+		c.bulkCreateRef(),
+		c.bulkPatchRef(),
+		c.bulkPatchItemRef(),
+		c.bulkDeleteRef(),
+		schemas.Ref("BulkDeleteResult", schemas.BulkDeleteSchema),
+		schemas.Ref("Problem", schemas.ErrorSchema),
+		schemas.Ref("ValidationProblem", schemas.ValidationErrorSchema),
+	} {
+		components[strings.TrimPrefix(ref.Ref, "#/components/schemas/")] = ref.Value.NewRef()
+	}
+
+	return components
+}
+
+func (c *crudApi[T]) CreateRoute() routing.Route {
+	responses := openapi3.NewResponses()
+
+	responses.Set("201", &openapi3.ResponseRef{
+		Value: &openapi3.Response{
+			Description: openapi3.Ptr(fmt.Sprintf("%s created successfully. The body is empty when return=minimal is preferred.", c.name)),
+			Headers: openapi3.Headers{
+				"Location": locationHeader(),
+			},
+			Content: openapi3.Content{
 				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
+					WithSchemaRef(c.itemRef()),
+			},
+		},
 	})
 
+	responses.Set("400", schemas.NewErrorResponse("Bad Request"))
+	responses.Set("401", schemas.NewErrorResponse("Unauthorized"))
+	responses.Set("403", schemas.NewErrorResponse("Forbidden"))
+	responses.Set("409", schemas.NewErrorResponse("Conflict"))
+	responses.Set("422", schemas.NewValidationErrorResponse("Unprocessable Entity"))
+	responses.Set("500", schemas.NewErrorResponse("Internal Server Error"))
+	responses.Set("503", schemas.NewErrorResponse("Service Unavailable"))
+	responses.Set("504", schemas.NewErrorResponse("Gateway Timeout"))
+
 	return routing.Route{
 		OpenAPIMetadata: routing.OpenAPIMetadata{
 			Summary:     fmt.Sprintf("Create %s", c.name),
 			Description: fmt.Sprintf("This endpoint creates a new %s.", strings.ToLower(c.name)),
 			Tags:        []string{fmt.Sprintf("%ss", c.name)},
-			Parameters:  nil,
+			Parameters: append([]*openapi3.ParameterRef{
+				preferParameter(),
+			}, c.tenantParameters()...),
 			RequestBody: &openapi3.RequestBodyRef{
 				Value: openapi3.NewRequestBody().
 					WithRequired(true).
-					WithJSONSchemaRef(c.create.NewRef()).
+					WithJSONSchemaRef(c.createRef()).
 					WithDescription(fmt.Sprintf("Payload to create a new %s.", strings.ToLower(c.name))),
 			},
 			Responses: responses,
+			Security:  c.security(OperationCreate),
 		},
-		Entity:       c.name,
-		CreateSchema: c.create,
-		UpdateSchema: nil,
-		Method:       routing.POST,
-		Path:         fmt.Sprintf("/%ss", strings.ToLower(c.name)),
-		Middlewares:  []fiber.Handler{},
+		Entity:          c.name,
+		Schemas:         c.schemas(),
+		SecuritySchemes: c.securitySchemes(),
+		Method:          routing.POST,
+		Path:            fmt.Sprintf("/%ss", strings.ToLower(c.name)),
+		Middlewares:     c.middlewares(OperationCreate),
 		Handler: func(ctx *fiber.Ctx) error {
+			queryCtx, cancel := c.queryContext(ctx, OperationCreate)
+			defer cancel()
+
 			var entity T
 
-			if err := ctx.BodyParser(&entity); err != nil {
-				return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-					"error":   "Bad Request",
-					"message": err.Error(),
-				})
+			fieldErrors, err := c.decodeBody(ctx.Body(), c.create, &entity)
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
 			}
 
-			if err := c.crud.Create(&entity); err != nil {
-				return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error":   "Internal Server Error",
-					"message": err.Error(),
-				})
+			if len(fieldErrors) > 0 {
+				return NewValidationError(fmt.Sprintf("The %s payload is invalid.", strings.ToLower(c.name)), fieldErrors)
 			}
 
-			return ctx.Status(fiber.StatusOK).SendString("OK")
+			if err := c.authorize(queryCtx, OperationCreate, &entity); err != nil {
+				return err
+			}
+
+			if err := c.guardWrites(queryCtx, nil, &entity); err != nil {
+				return err
+			}
+
+			if err := transaction(queryCtx, c.storage, func(txCtx context.Context) error {
+				if err := runHooks(txCtx, c.hooks.beforeCreate, &entity); err != nil {
+					return err
+				}
+
+				if err := c.crud.Create(txCtx, &entity); err != nil {
+					return err
+				}
+
+				return runHooks(txCtx, c.hooks.afterCreate, &entity)
+			}); err != nil {
+				return err
+			}
+
+			ctx.Location(fmt.Sprintf("%s/%v", strings.TrimSuffix(ctx.Path(), "/"), c.model.primaryKey(&entity)))
+
+			if preferMinimal(ctx) {
+				return c.respond(ctx, OperationCreate, fiber.StatusCreated, &entity)
+			}
+
+			if err := runHooks(queryCtx, c.hooks.afterFind, &entity); err != nil {
+				return err
+			}
+
+			return c.respond(ctx, OperationCreate, fiber.StatusCreated, &entity)
 		},
 	}
 }
@@ -170,123 +431,283 @@ func (c *crudApi[T]) UpdateRoute() routing.Route {
 
 	responses.Set("200", &openapi3.ResponseRef{
 		Value: openapi3.NewResponse().
-			WithDescription(fmt.Sprintf("%s updated successfully", c.name)).
-			WithContent(openapi3.Content{
-				"text/plain": openapi3.NewMediaType().
-					WithSchema(openapi3.NewStringSchema().WithDefault("OK")),
-			}),
-	})
-
-	responses.Set("400", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Bad Request").
+			WithDescription(fmt.Sprintf("%s updated successfully.", c.name)).
 			WithContent(openapi3.Content{
 				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
+					WithSchemaRef(c.itemRef()),
 			}),
 	})
 
-	responses.Set("401", &openapi3.ResponseRef{
+	responses.Set("204", &openapi3.ResponseRef{
 		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Unauthorized").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
+			WithDescription(fmt.Sprintf("%s updated successfully, returned when return=minimal is preferred.", c.name)),
 	})
 
-	responses.Set("403", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Forbidden").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
-	})
+	responses.Set("400", schemas.NewErrorResponse("Bad Request"))
+	responses.Set("401", schemas.NewErrorResponse("Unauthorized"))
+	responses.Set("403", schemas.NewErrorResponse("Forbidden"))
+	responses.Set("404", schemas.NewErrorResponse("Not Found"))
+	responses.Set("409", schemas.NewErrorResponse("Conflict"))
+	responses.Set("412", schemas.NewErrorResponse("Precondition Failed"))
+	responses.Set("422", schemas.NewValidationErrorResponse("Unprocessable Entity"))
+	responses.Set("428", schemas.NewErrorResponse("Precondition Required"))
+	responses.Set("500", schemas.NewErrorResponse("Internal Server Error"))
+	responses.Set("503", schemas.NewErrorResponse("Service Unavailable"))
+	responses.Set("504", schemas.NewErrorResponse("Gateway Timeout"))
+
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     fmt.Sprintf("Update %s", c.name),
+			Description: fmt.Sprintf("This endpoint updates an existing %s.", strings.ToLower(c.name)),
+			Tags:        []string{fmt.Sprintf("%ss", c.name)},
+			Parameters: append([]*openapi3.ParameterRef{
+				{
+					Value: openapi3.NewPathParameter("id").
+						WithRequired(true).
+						WithSchema(openapi3.NewUUIDSchema()),
+				},
+				preferParameter(),
+				ifMatchParameter(),
+			}, c.tenantParameters()...),
+			RequestBody: &openapi3.RequestBodyRef{
+				Value: openapi3.NewRequestBody().
+					WithRequired(true).
+					WithJSONSchemaRef(c.updateRef()).
+					WithDescription(fmt.Sprintf("Payload to update an existing %s.", strings.ToLower(c.name))),
+			},
+			Responses: responses,
+			Security:  c.security(OperationUpdate),
+		},
+		Entity:          c.name,
+		Schemas:         c.schemas(),
+		SecuritySchemes: c.securitySchemes(),
+		Method:          routing.PUT,
+		Path:            fmt.Sprintf("/%ss/{id}", strings.ToLower(c.name)),
+		Middlewares:     c.middlewares(OperationUpdate),
+		Handler: func(ctx *fiber.Ctx) error {
+			queryCtx, cancel := c.queryContext(ctx, OperationUpdate)
+			defer cancel()
+
+			var params UpdateParams
+
+			if err := ctx.ParamsParser(&params); err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			id, err := c.model.parseId(params.Id)
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
 
-	responses.Set("404", &openapi3.ResponseRef{
+			queryCtx, err = c.precondition(ctx, queryCtx)
+
+			if err != nil {
+				return err
+			}
+
+			var entity T
+
+			fieldErrors, err := c.decodeBody(ctx.Body(), c.update, &entity)
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			if len(fieldErrors) > 0 {
+				return NewValidationError(fmt.Sprintf("The %s payload is invalid.", strings.ToLower(c.name)), fieldErrors)
+			}
+
+			if err := c.model.setPrimaryKey(&entity, id); err != nil {
+				return err
+			}
+
+			if err := transaction(queryCtx, c.storage, func(txCtx context.Context) error {
+				if c.guarded(OperationUpdate) || len(c.writeRoles) > 0 {
+					var current T
+
+					if err := c.load(txCtx, OperationUpdate, id, nil, &current); err != nil {
+						return err
+					}
+
+					if err := c.guardWrites(txCtx, &current, &entity); err != nil {
+						return err
+					}
+				}
+
+				if err := runHooks(txCtx, c.hooks.beforeUpdate, &entity); err != nil {
+					return err
+				}
+
+				if err := c.crud.Update(txCtx, id, &entity); err != nil {
+					return err
+				}
+
+				return runHooks(txCtx, c.hooks.afterUpdate, &entity)
+			}); err != nil {
+				return err
+			}
+
+			if preferMinimal(ctx) {
+				return c.respond(ctx, OperationUpdate, fiber.StatusOK, &entity)
+			}
+
+			if err := c.crud.FindOne(queryCtx, id, nil, &entity); err != nil {
+				return err
+			}
+
+			if err := runHooks(queryCtx, c.hooks.afterFind, &entity); err != nil {
+				return err
+			}
+
+			return c.respond(ctx, OperationUpdate, fiber.StatusOK, &entity)
+		},
+	}
+}
+
+func (c *crudApi[T]) PatchRoute() routing.Route {
+	responses := openapi3.NewResponses()
+
+	responses.Set("200", &openapi3.ResponseRef{
 		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Not Found").
+			WithDescription(fmt.Sprintf("%s patched successfully.", c.name)).
 			WithContent(openapi3.Content{
 				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
+					WithSchemaRef(c.itemRef()),
 			}),
 	})
 
-	responses.Set("500", &openapi3.ResponseRef{
+	responses.Set("204", &openapi3.ResponseRef{
 		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Internal Server Error").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
+			WithDescription(fmt.Sprintf("%s patched successfully, returned when return=minimal is preferred.", c.name)),
 	})
 
+	responses.Set("400", schemas.NewErrorResponse("Bad Request"))
+	responses.Set("401", schemas.NewErrorResponse("Unauthorized"))
+	responses.Set("403", schemas.NewErrorResponse("Forbidden"))
+	responses.Set("404", schemas.NewErrorResponse("Not Found"))
+	responses.Set("409", schemas.NewErrorResponse("Conflict"))
+	responses.Set("412", schemas.NewErrorResponse("Precondition Failed"))
+	responses.Set("415", schemas.NewErrorResponse("Unsupported Media Type"))
+	responses.Set("422", schemas.NewValidationErrorResponse("Unprocessable Entity"))
+	responses.Set("428", schemas.NewErrorResponse("Precondition Required"))
+	responses.Set("500", schemas.NewErrorResponse("Internal Server Error"))
+	responses.Set("503", schemas.NewErrorResponse("Service Unavailable"))
+	responses.Set("504", schemas.NewErrorResponse("Gateway Timeout"))
+
 	return routing.Route{
 		OpenAPIMetadata: routing.OpenAPIMetadata{
-			Summary:     fmt.Sprintf("Update %s", c.name),
-			Description: fmt.Sprintf("This endpoint updates an existing %s.", strings.ToLower(c.name)),
+			Summary:     fmt.Sprintf("Patch %s", c.name),
+			Description: fmt.Sprintf("This endpoint partially updates an existing %s using a JSON merge patch or a JSON patch.", strings.ToLower(c.name)),
 			Tags:        []string{fmt.Sprintf("%ss", c.name)},
-			Parameters: []*openapi3.ParameterRef{
+			Parameters: append([]*openapi3.ParameterRef{
 				{
 					Value: openapi3.NewPathParameter("id").
 						WithRequired(true).
 						WithSchema(openapi3.NewUUIDSchema()),
 				},
-			},
+				preferParameter(),
+				ifMatchParameter(),
+			}, c.tenantParameters()...),
 			RequestBody: &openapi3.RequestBodyRef{
 				Value: openapi3.NewRequestBody().
 					WithRequired(true).
-					WithJSONSchemaRef(c.update.NewRef()).
-					WithDescription(fmt.Sprintf("Payload to update an existing %s.", strings.ToLower(c.name))),
+					WithContent(openapi3.Content{
+						MergePatchContentType: openapi3.NewMediaType().
+							WithSchemaRef(c.patchRef()),
+						JSONPatchContentType: openapi3.NewMediaType().
+							WithSchemaRef(schemas.Ref("JSONPatch", schemas.JSONPatchSchema)),
+					}).
+					WithDescription(fmt.Sprintf("Patch document to apply to an existing %s.", strings.ToLower(c.name))),
 			},
 			Responses: responses,
+			Security:  c.security(OperationPatch),
 		},
-		Entity:       c.name,
-		CreateSchema: nil,
-		UpdateSchema: c.update,
-		Method:       routing.PUT,
-		Path:         fmt.Sprintf("/%ss/:id", strings.ToLower(c.name)),
-		Middlewares:  []fiber.Handler{},
+		Entity:          c.name,
+		Schemas:         c.schemas(),
+		SecuritySchemes: c.securitySchemes(),
+		Method:          routing.PATCH,
+		Path:            fmt.Sprintf("/%ss/{id}", strings.ToLower(c.name)),
+		Middlewares:     c.middlewares(OperationPatch),
 		Handler: func(ctx *fiber.Ctx) error {
+			queryCtx, cancel := c.queryContext(ctx, OperationPatch)
+			defer cancel()
+
 			var params UpdateParams
 
 			if err := ctx.ParamsParser(&params); err != nil {
-				return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-					"error":   "Bad Request",
-					"message": err.Error(),
-				})
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			id, err := c.model.parseId(params.Id)
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			queryCtx, err = c.precondition(ctx, queryCtx)
+
+			if err != nil {
+				return err
+			}
+
+			mediaType, _, err := mime.ParseMediaType(ctx.Get(fiber.HeaderContentType))
+
+			if err != nil || (mediaType != MergePatchContentType && mediaType != JSONPatchContentType && mediaType != fiber.MIMEApplicationJSON) {
+				return NewError(KindUnsupportedMediaType, fmt.Sprintf("The request body must be %s or %s.", MergePatchContentType, JSONPatchContentType))
 			}
 
 			var entity T
 
-			if err := ctx.BodyParser(&entity); err != nil {
-				return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-					"error":   "Bad Request",
-					"message": err.Error(),
-				})
+			if err := c.load(queryCtx, OperationPatch, id, nil, &entity); err != nil {
+				return err
+			}
+
+			current := entity
+
+			columns, fieldErrors, err := c.patchEntity(queryCtx, mediaType, ctx.Body(), &entity)
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
 			}
 
-			if err := c.crud.Update(params.Id, &entity); err != nil {
-				if err == gorm.ErrRecordNotFound {
-					return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
-						"error":   "Not Found",
-						"message": fmt.Sprintf("The %s was not found.", strings.ToLower(c.name)),
-					})
+			if len(fieldErrors) > 0 {
+				return NewValidationError(fmt.Sprintf("The patched %s is invalid.", strings.ToLower(c.name)), fieldErrors)
+			}
+
+			if err := c.guardWrites(queryCtx, &current, &entity); err != nil {
+				return err
+			}
+
+			if err := transaction(queryCtx, c.storage, func(txCtx context.Context) error {
+				prepared := entity
+
+				if err := runHooks(txCtx, c.hooks.beforeUpdate, &entity); err != nil {
+					return err
+				}
+
+				if err := c.crud.Patch(txCtx, id, &entity, mergeColumns(columns, c.model.changedColumns(&prepared, &entity))); err != nil {
+					return err
 				}
 
-				return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error":   "Internal Server Error",
-					"message": err.Error(),
-				})
+				return runHooks(txCtx, c.hooks.afterUpdate, &entity)
+			}); err != nil {
+				return err
 			}
 
-			return ctx.Status(fiber.StatusOK).SendString("OK")
+			if preferMinimal(ctx) {
+				return c.respond(ctx, OperationPatch, fiber.StatusOK, &entity)
+			}
+
+			if err := c.crud.FindOne(queryCtx, id, nil, &entity); err != nil {
+				return err
+			}
+
+			if err := runHooks(queryCtx, c.hooks.afterFind, &entity); err != nil {
+				return err
+			}
+
+			return c.respond(ctx, OperationPatch, fiber.StatusOK, &entity)
 		},
 	}
 }
@@ -303,102 +724,186 @@ func (c *crudApi[T]) DeleteRoute() routing.Route {
 			}),
 	})
 
-	responses.Set("400", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Bad Request").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
-	})
+	responses.Set("400", schemas.NewErrorResponse("Bad Request"))
+	responses.Set("401", schemas.NewErrorResponse("Unauthorized"))
+	responses.Set("403", schemas.NewErrorResponse("Forbidden"))
+	responses.Set("404", schemas.NewErrorResponse("Not Found"))
+	responses.Set("409", schemas.NewErrorResponse("Conflict"))
+	responses.Set("412", schemas.NewErrorResponse("Precondition Failed"))
+	responses.Set("428", schemas.NewErrorResponse("Precondition Required"))
+	responses.Set("500", schemas.NewErrorResponse("Internal Server Error"))
+	responses.Set("503", schemas.NewErrorResponse("Service Unavailable"))
+	responses.Set("504", schemas.NewErrorResponse("Gateway Timeout"))
 
-	responses.Set("401", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Unauthorized").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
-	})
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     fmt.Sprintf("Delete %s", c.name),
+			Description: c.deleteDescription(),
+			Tags:        []string{fmt.Sprintf("%ss", c.name)},
+			Parameters:  append(c.deleteParameters(), ifMatchParameter()),
+			RequestBody: nil,
+			Responses:   responses,
+			Security:    c.security(OperationDelete),
+		},
+		Entity:          c.name,
+		Schemas:         c.schemas(),
+		SecuritySchemes: c.securitySchemes(),
+		Method:          routing.DELETE,
+		Path:            fmt.Sprintf("/%ss/{id}", strings.ToLower(c.name)),
+		Middlewares:     c.middlewares(OperationDelete),
+		Handler: func(ctx *fiber.Ctx) error {
+			queryCtx, cancel := c.queryContext(ctx, OperationDelete)
+			defer cancel()
 
-	responses.Set("403", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Forbidden").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
-	})
+			var params DeleteParams
 
-	responses.Set("404", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Not Found").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
-	})
+			if err := ctx.ParamsParser(&params); err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			id, err := c.model.parseId(params.Id)
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			queryCtx, err = c.precondition(ctx, queryCtx)
+
+			if err != nil {
+				return err
+			}
+
+			hard := c.model.softDeletable() && ctx.QueryBool("hard")
+
+			if hard && (c.hardDelete == nil || !c.hardDelete(ctx)) {
+				return NewError(KindForbidden, fmt.Sprintf("You are not allowed to permanently delete this %s.", strings.ToLower(c.name)))
+			}
+
+			remove := c.crud.Delete
+
+			if hard {
+				remove = c.crud.HardDelete
+			}
+
+			var entity T
+
+			if err := transaction(queryCtx, c.storage, func(txCtx context.Context) error {
+				if len(c.hooks.beforeDelete) > 0 || len(c.hooks.afterDelete) > 0 || c.guarded(OperationDelete) {
+					if err := c.load(txCtx, OperationDelete, id, &Query{Unscoped: hard}, &entity); err != nil {
+						return err
+					}
+				}
+
+				if err := runHooks(txCtx, c.hooks.beforeDelete, &entity); err != nil {
+					return err
+				}
+
+				if err := remove(txCtx, id, &entity); err != nil {
+					return err
+				}
 
-	responses.Set("500", &openapi3.ResponseRef{
+				return runHooks(txCtx, c.hooks.afterDelete, &entity)
+			}); err != nil {
+				return err
+			}
+
+			return ctx.Status(fiber.StatusOK).SendString("OK")
+		},
+	}
+}
+
+func (c *crudApi[T]) RestoreRoute() routing.Route {
+	if !c.model.softDeletable() {
+		panic(fmt.Sprintf("cannot restore %s because it does not support soft deletes", c.name))
+	}
+
+	responses := openapi3.NewResponses()
+
+	responses.Set("200", &openapi3.ResponseRef{
 		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Internal Server Error").
+			WithDescription(fmt.Sprintf("%s restored successfully.", c.name)).
 			WithContent(openapi3.Content{
 				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
+					WithSchemaRef(c.itemRef()),
 			}),
 	})
 
+	responses.Set("400", schemas.NewErrorResponse("Bad Request"))
+	responses.Set("401", schemas.NewErrorResponse("Unauthorized"))
+	responses.Set("403", schemas.NewErrorResponse("Forbidden"))
+	responses.Set("404", schemas.NewErrorResponse("Not Found"))
+	responses.Set("409", schemas.NewErrorResponse("Conflict"))
+	responses.Set("500", schemas.NewErrorResponse("Internal Server Error"))
+	responses.Set("503", schemas.NewErrorResponse("Service Unavailable"))
+	responses.Set("504", schemas.NewErrorResponse("Gateway Timeout"))
+
 	return routing.Route{
 		OpenAPIMetadata: routing.OpenAPIMetadata{
-			Summary:     fmt.Sprintf("Delete %s", c.name),
-			Description: fmt.Sprintf("This endpoint deletes an existing %s.", strings.ToLower(c.name)),
+			Summary:     fmt.Sprintf("Restore %s", c.name),
+			Description: fmt.Sprintf("This endpoint restores a soft deleted %s from the trash.", strings.ToLower(c.name)),
 			Tags:        []string{fmt.Sprintf("%ss", c.name)},
-			Parameters: []*openapi3.ParameterRef{
+			Parameters: append([]*openapi3.ParameterRef{
 				{
 					Value: openapi3.NewPathParameter("id").
 						WithRequired(true).
 						WithSchema(openapi3.NewUUIDSchema()),
 				},
-			},
+			}, c.tenantParameters()...),
 			RequestBody: nil,
 			Responses:   responses,
+			Security:    c.security(OperationRestore),
 		},
-		Entity:       c.name,
-		CreateSchema: nil,
-		UpdateSchema: nil,
-		Method:       routing.DELETE,
-		Path:         fmt.Sprintf("/%ss/:id", strings.ToLower(c.name)),
-		Middlewares:  []fiber.Handler{},
+		Entity:          c.name,
+		Schemas:         c.schemas(),
+		SecuritySchemes: c.securitySchemes(),
+		Method:          routing.POST,
+		Path:            fmt.Sprintf("/%ss/{id}/restore", strings.ToLower(c.name)),
+		Middlewares:     c.middlewares(OperationRestore),
 		Handler: func(ctx *fiber.Ctx) error {
-			var params DeleteParams
+			queryCtx, cancel := c.queryContext(ctx, OperationRestore)
+			defer cancel()
+
+			var params RestoreParams
 
 			if err := ctx.ParamsParser(&params); err != nil {
-				return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-					"error":   "Bad Request",
-					"message": err.Error(),
-				})
-			}
-
-			if err := c.crud.Delete(params.Id, new(T)); err != nil {
-				if err == gorm.ErrRecordNotFound {
-					return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
-						"error":   "Not Found",
-						"message": fmt.Sprintf("The %s was not found.", strings.ToLower(c.name)),
-					})
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			id, err := c.model.parseId(params.Id)
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			var entity T
+
+			if c.guarded(OperationRestore) {
+				if err := c.load(queryCtx, OperationRestore, id, &Query{Unscoped: true}, &entity); err != nil {
+					return err
 				}
+			}
 
-				return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error":   "Internal Server Error",
-					"message": err.Error(),
-				})
+			if err := c.crud.Restore(queryCtx, id, &entity); err != nil {
+				return err
 			}
 
-			return ctx.Status(fiber.StatusOK).SendString("OK")
+			if err := c.crud.FindOne(queryCtx, id, nil, &entity); err != nil {
+				return err
+			}
+
+			if err := runHooks(queryCtx, c.hooks.afterFind, &entity); err != nil {
+				return err
+			}
+
+			item, _, err := c.present(queryCtx, &entity, nil)
+
+			if err != nil {
+				return err
+			}
+
+			return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+				"item": item,
+			})
 		},
 	}
 }
@@ -407,114 +912,104 @@ func (c *crudApi[T]) GetOneRoute() routing.Route {
 	responses := openapi3.NewResponses()
 
 	responses.Set("200", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithDescription(fmt.Sprintf("%s retrieved successfully.", c.name)).
-			WithContent(openapi3.Content{
+		Value: &openapi3.Response{
+			Description: openapi3.Ptr(fmt.Sprintf("%s retrieved successfully.", c.name)),
+			Headers:     cacheHeaders(true),
+			Content: openapi3.Content{
 				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.SuccessSchema),
-			}),
-	})
-
-	responses.Set("400", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Bad Request").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
-	})
-
-	responses.Set("401", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Unauthorized").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
-	})
-
-	responses.Set("403", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Forbidden").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
+					WithSchemaRef(c.itemRef()),
+			},
+		},
 	})
 
-	responses.Set("404", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Not Found").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
-	})
+	responses.Set("304", notModifiedResponse(true))
 
-	responses.Set("500", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Internal Server Error").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
-	})
+	responses.Set("400", schemas.NewErrorResponse("Bad Request"))
+	responses.Set("401", schemas.NewErrorResponse("Unauthorized"))
+	responses.Set("403", schemas.NewErrorResponse("Forbidden"))
+	responses.Set("404", schemas.NewErrorResponse("Not Found"))
+	responses.Set("500", schemas.NewErrorResponse("Internal Server Error"))
+	responses.Set("503", schemas.NewErrorResponse("Service Unavailable"))
+	responses.Set("504", schemas.NewErrorResponse("Gateway Timeout"))
 
 	return routing.Route{
 		OpenAPIMetadata: routing.OpenAPIMetadata{
 			Summary:     fmt.Sprintf("Get %s", c.name),
 			Description: fmt.Sprintf("This endpoint retrieves an existing %s.", strings.ToLower(c.name)),
 			Tags:        []string{fmt.Sprintf("%ss", c.name)},
-			Parameters: []*openapi3.ParameterRef{
-				{
-					Value: openapi3.NewPathParameter("id").
-						WithRequired(true).
-						WithSchema(openapi3.NewUUIDSchema()),
+			Parameters: append(
+				[]*openapi3.ParameterRef{
+					{
+						Value: openapi3.NewPathParameter("id").
+							WithRequired(true).
+							WithSchema(openapi3.NewUUIDSchema()),
+					},
 				},
-			},
+				append(append(c.model.selectionParameters(), conditionalParameters(true)...), c.tenantParameters()...)...,
+			),
 			RequestBody: nil,
 			Responses:   responses,
+			Security:    c.security(OperationGetOne),
 		},
-		Entity:       c.name,
-		CreateSchema: nil,
-		UpdateSchema: nil,
-		Method:       routing.GET,
-		Path:         fmt.Sprintf("/%ss/:id", strings.ToLower(c.name)),
-		Middlewares:  []fiber.Handler{},
+		Entity:          c.name,
+		Schemas:         c.schemas(),
+		SecuritySchemes: c.securitySchemes(),
+		Method:          routing.GET,
+		Path:            fmt.Sprintf("/%ss/{id}", strings.ToLower(c.name)),
+		Middlewares:     c.middlewares(OperationGetOne),
 		Handler: func(ctx *fiber.Ctx) error {
+			queryCtx, cancel := c.queryContext(ctx, OperationGetOne)
+			defer cancel()
+
 			var params GetOneParams
 
 			if err := ctx.ParamsParser(&params); err != nil {
-				return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-					"error":   "Bad Request",
-					"message": err.Error(),
-				})
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			id, err := c.model.parseId(params.Id)
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			query := &Query{}
+
+			if err := c.parseSelection(ctx, query); err != nil {
+				return NewError(KindBadRequest, err.Error())
 			}
 
 			var entity T
 
-			if err := c.crud.FindOne(params.Id, &entity); err != nil {
-				if err == gorm.ErrRecordNotFound {
-					return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
-						"error":   "Not Found",
-						"message": fmt.Sprintf("The %s was not found.", strings.ToLower(c.name)),
-					})
-				}
+			if err := c.load(queryCtx, OperationGetOne, id, query, &entity); err != nil {
+				return err
+			}
+
+			if err := runHooks(queryCtx, c.hooks.afterFind, &entity); err != nil {
+				return err
+			}
 
-				return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error":   "Internal Server Error",
-					"message": err.Error(),
-				})
+			item, redacted, err := c.present(queryCtx, &entity, query.Fields)
+
+			if err != nil {
+				return err
 			}
 
-			return ctx.Status(fiber.StatusOK).JSON(&fiber.Map{
-				"item": entity,
+			body, err := ctx.App().Config().JSONEncoder(fiber.Map{
+				"item": item,
 			})
+
+			if err != nil {
+				return err
+			}
+
+			etag := ""
+
+			if query.Fields == nil && len(query.Expand) == 0 && !redacted {
+				etag = c.model.etag(&entity)
+			}
+
+			return c.send(ctx, OperationGetOne, body, etag, c.model.lastModified(&entity))
 		},
 	}
 }
@@ -523,82 +1018,154 @@ func (c *crudApi[T]) GetAllRoute() routing.Route {
 	responses := openapi3.NewResponses()
 
 	responses.Set("200", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithDescription(fmt.Sprintf("%s's retrieved successfully.", c.name)).
-			WithContent(openapi3.Content{
+		Value: &openapi3.Response{
+			Description: openapi3.Ptr(fmt.Sprintf("%s's retrieved successfully.", c.name)),
+			Headers:     cacheHeaders(false),
+			Content: openapi3.Content{
 				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.SuccessSchema),
-			}),
+					WithSchemaRef(c.listRef()),
+			},
+		},
 	})
 
-	responses.Set("400", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Bad Request").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
-	})
+	responses.Set("304", notModifiedResponse(false))
 
-	responses.Set("401", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Unauthorized").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
-	})
+	responses.Set("400", schemas.NewErrorResponse("Bad Request"))
+	responses.Set("401", schemas.NewErrorResponse("Unauthorized"))
+	responses.Set("403", schemas.NewErrorResponse("Forbidden"))
+	responses.Set("500", schemas.NewErrorResponse("Internal Server Error"))
+	responses.Set("503", schemas.NewErrorResponse("Service Unavailable"))
+	responses.Set("504", schemas.NewErrorResponse("Gateway Timeout"))
 
-	responses.Set("403", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Forbidden").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
-	})
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     fmt.Sprintf("Get %ss", c.name),
+			Description: fmt.Sprintf("This endpoint retrieves a list of %ss.", strings.ToLower(c.name)),
+			Tags:        []string{fmt.Sprintf("%ss", c.name)},
+			Parameters:  append(c.listParameters(), conditionalParameters(false)...),
+			RequestBody: nil,
+			Responses:   responses,
+			Security:    c.security(OperationGetAll),
+		},
+		Entity:          c.name,
+		Schemas:         c.schemas(),
+		SecuritySchemes: c.securitySchemes(),
+		Method:          routing.GET,
+		Path:            fmt.Sprintf("/%ss", strings.ToLower(c.name)),
+		Middlewares:     c.middlewares(OperationGetAll),
+		Handler: func(ctx *fiber.Ctx) error {
+			queryCtx, cancel := c.queryContext(ctx, OperationGetAll)
+			defer cancel()
 
-	responses.Set("500", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
-			WithJSONSchema(schemas.ErrorSchema).
-			WithDescription("Internal Server Error").
-			WithContent(openapi3.Content{
+			query, err := c.parseListQuery(ctx)
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			if err := c.authorize(queryCtx, OperationGetAll, nil); err != nil {
+				return err
+			}
+
+			query, err = c.scope(queryCtx, query)
+
+			if err != nil {
+				return err
+			}
+
+			entities := []T{}
+
+			page, err := c.crud.FindPage(queryCtx, query, &entities)
+
+			if err != nil {
+				return err
+			}
+
+			if err := runHooksAll(queryCtx, c.hooks.afterFind, entities); err != nil {
+				return err
+			}
+
+			return c.respondList(ctx, OperationGetAll, query, page, entities)
+		},
+	}
+}
+
+func (c *crudApi[T]) TrashRoute() routing.Route {
+	if !c.model.softDeletable() {
+		panic(fmt.Sprintf("cannot list trashed %ss because %s does not support soft deletes", strings.ToLower(c.name), c.name))
+	}
+
+	responses := openapi3.NewResponses()
+
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: &openapi3.Response{
+			Description: openapi3.Ptr(fmt.Sprintf("Trashed %s's retrieved successfully.", c.name)),
+			Headers:     cacheHeaders(false),
+			Content: openapi3.Content{
 				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.ErrorSchema),
-			}),
+					WithSchemaRef(c.listRef()),
+			},
+		},
 	})
 
+	responses.Set("304", notModifiedResponse(false))
+
+	responses.Set("400", schemas.NewErrorResponse("Bad Request"))
+	responses.Set("401", schemas.NewErrorResponse("Unauthorized"))
+	responses.Set("403", schemas.NewErrorResponse("Forbidden"))
+	responses.Set("500", schemas.NewErrorResponse("Internal Server Error"))
+	responses.Set("503", schemas.NewErrorResponse("Service Unavailable"))
+	responses.Set("504", schemas.NewErrorResponse("Gateway Timeout"))
+
 	return routing.Route{
 		OpenAPIMetadata: routing.OpenAPIMetadata{
-			Summary:     fmt.Sprintf("Get %ss", c.name),
-			Description: fmt.Sprintf("This endpoint retrieves a list of %ss.", strings.ToLower(c.name)),
+			Summary:     fmt.Sprintf("Get trashed %ss", c.name),
+			Description: fmt.Sprintf("This endpoint retrieves a list of soft deleted %ss.", strings.ToLower(c.name)),
 			Tags:        []string{fmt.Sprintf("%ss", c.name)},
-			Parameters:  nil,
+			Parameters:  append(c.listParameters(), conditionalParameters(false)...),
 			RequestBody: nil,
 			Responses:   responses,
+			Security:    c.security(OperationTrash),
 		},
-		Entity:       c.name,
-		CreateSchema: nil,
-		UpdateSchema: nil,
-		Method:       routing.GET,
-		Path:         fmt.Sprintf("/%ss", strings.ToLower(c.name)),
-		Middlewares:  []fiber.Handler{},
+		Entity:          c.name,
+		Schemas:         c.schemas(),
+		SecuritySchemes: c.securitySchemes(),
+		Method:          routing.GET,
+		Path:            fmt.Sprintf("/%ss/trash", strings.ToLower(c.name)),
+		Middlewares:     c.middlewares(OperationTrash),
 		Handler: func(ctx *fiber.Ctx) error {
-			var entities []T
+			queryCtx, cancel := c.queryContext(ctx, OperationTrash)
+			defer cancel()
 
-			if err := c.crud.FindAll(&entities); err != nil {
-				return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error":   "Internal Server Error",
-					"message": err.Error(),
-				})
+			query, err := c.parseListQuery(ctx)
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
 			}
 
-			return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
-				"items": entities,
-			})
+			if err := c.authorize(queryCtx, OperationTrash, nil); err != nil {
+				return err
+			}
+
+			query, err = c.scope(queryCtx, query)
+
+			if err != nil {
+				return err
+			}
+
+			entities := []T{}
+
+			page, err := c.crud.FindTrash(queryCtx, query, &entities)
+
+			if err != nil {
+				return err
+			}
+
+			if err := runHooksAll(queryCtx, c.hooks.afterFind, entities); err != nil {
+				return err
+			}
+
+			return c.respondList(ctx, OperationTrash, query, page, entities)
 		},
 	}
 }
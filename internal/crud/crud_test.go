@@ -0,0 +1,134 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/connor-davis/dynamic-crud/internal/routing"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestAffected(t *testing.T) {
+	widgets := &crud[testWidget]{model: parseModel[testWidget]()}
+	failure := errors.New("connection reset")
+
+	tests := []struct {
+		name         string
+		result       *gorm.DB
+		wantNotFound bool
+		wantErr      error
+	}{
+		{name: "row affected", result: &gorm.DB{RowsAffected: 1}},
+		{name: "several rows affected", result: &gorm.DB{RowsAffected: 3}},
+		{name: "no rows affected", result: &gorm.DB{}, wantNotFound: true},
+		{name: "query failed", result: &gorm.DB{Error: failure}, wantErr: failure},
+		{name: "query failed without rows", result: &gorm.DB{Error: failure, RowsAffected: 0}, wantErr: failure},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := widgets.affected("42", test.result)
+
+			switch {
+			case test.wantNotFound:
+				var notFound *NotFoundError
+
+				if !errors.As(err, &notFound) || notFound.Entity != "testWidget" || notFound.Id != "42" {
+					t.Fatalf("expected a not found error for the widget, got %v", err)
+				}
+			case test.wantErr != nil:
+				if !errors.Is(err, test.wantErr) || IsNotFound(err) {
+					t.Fatalf("expected %v, got %v", test.wantErr, err)
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCrudNotFoundWhenNothingMatches(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name string
+		run  func(ctx context.Context, widgets Crud[testWidget]) error
+	}{
+		{name: "update", run: func(ctx context.Context, widgets Crud[testWidget]) error {
+			return widgets.Update(ctx, id, &testWidget{Id: id, Name: "widget"})
+		}},
+		{name: "patch", run: func(ctx context.Context, widgets Crud[testWidget]) error {
+			return widgets.Patch(ctx, id, &testWidget{Id: id, Name: "widget"}, []string{"name"})
+		}},
+		{name: "delete", run: func(ctx context.Context, widgets Crud[testWidget]) error {
+			return widgets.Delete(ctx, id, &testWidget{})
+		}},
+		{name: "hard delete", run: func(ctx context.Context, widgets Crud[testWidget]) error {
+			return widgets.HardDelete(ctx, id, &testWidget{})
+		}},
+		{name: "restore", run: func(ctx context.Context, widgets Crud[testWidget]) error {
+			return widgets.Restore(ctx, id, &testWidget{})
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			widgets := &crud[testWidget]{model: parseModel[testWidget]()}
+			ctx := WithTransaction(context.Background(), newDryRun(t))
+
+			err := test.run(ctx, widgets)
+
+			if !IsNotFound(err) {
+				t.Fatalf("expected a not found error, got %v", err)
+			}
+
+			if kind := asError(err).Kind; kind != KindNotFound {
+				t.Fatalf("expected the %s kind, got %s", KindNotFound, kind)
+			}
+		})
+	}
+}
+
+func TestRoutesRejectMalformedIds(t *testing.T) {
+	api := newTestApi[testWidget](t, nil)
+
+	tests := []struct {
+		name   string
+		route  routing.Route
+		method string
+		path   string
+		body   string
+	}{
+		{name: "get one", route: api.GetOneRoute(), method: fiber.MethodGet, path: "/testwidgets/42"},
+		{name: "update", route: api.UpdateRoute(), method: fiber.MethodPut, path: "/testwidgets/not-a-uuid", body: `{"name":"widget"}`},
+		{name: "patch", route: api.PatchRoute(), method: fiber.MethodPatch, path: "/testwidgets/not-a-uuid", body: `{"name":"widget"}`},
+		{name: "delete", route: api.DeleteRoute(), method: fiber.MethodDelete, path: "/testwidgets/1234"},
+		{name: "restore", route: api.RestoreRoute(), method: fiber.MethodPost, path: "/testwidgets/nope/restore"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := newTestApp(test.route)
+			request := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			request.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+			response, err := app.Test(request)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			body, _ := io.ReadAll(response.Body)
+
+			if response.StatusCode != fiber.StatusBadRequest || !strings.Contains(string(body), "is not a valid testwidget id") {
+				t.Fatalf("expected status %d for the malformed id, got %d: %s", fiber.StatusBadRequest, response.StatusCode, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package crud
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type Kind string
+
+const (
+	KindBadRequest           Kind = "bad-request"
+	KindUnauthorized         Kind = "unauthorized"
+	KindForbidden            Kind = "forbidden"
+	KindNotFound             Kind = "not-found"
+	KindConflict             Kind = "conflict"
+	KindPreconditionFailed   Kind = "precondition-failed"
+	KindPreconditionRequired Kind = "precondition-required"
+	KindUnsupportedMediaType Kind = "unsupported-media-type"
+	KindValidation           Kind = "validation"
+	KindInternal             Kind = "internal"
+	KindUnavailable          Kind = "unavailable"
+	KindTimeout              Kind = "timeout"
+	KindCanceled             Kind = "canceled"
+)
+
+type Error struct {
+	Kind   Kind
+	Detail string
+	Errors []FieldError
+	Err    error
+}
+
+func NewError(kind Kind, detail string) *Error {
+	return &Error{
+		Kind:   kind,
+		Detail: detail,
+	}
+}
+
+func NewValidationError(detail string, fieldErrors []FieldError) *Error {
+	return &Error{
+		Kind:   KindValidation,
+		Detail: detail,
+		Errors: fieldErrors,
+	}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Kind, e.Detail, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %s", e.Kind, e.Detail)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+type NotFoundError struct {
+	Entity string
+	Id     any
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s %v was not found", strings.ToLower(e.Entity), e.Id)
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == gorm.ErrRecordNotFound
+}
+
+func IsNotFound(err error) bool {
+	var notFoundError *NotFoundError
+
+	return errors.As(err, &notFoundError)
+}
+
+func asError(err error) *Error {
+	var crudError *Error
+	var notFoundError *NotFoundError
+
+	switch {
+	case errors.As(err, &crudError):
+		return crudError
+	case errors.As(err, &notFoundError):
+		return &Error{
+			Kind:   KindNotFound,
+			Detail: fmt.Sprintf("The %s was not found.", strings.ToLower(notFoundError.Entity)),
+			Err:    err,
+		}
+	case errors.Is(err, context.Canceled):
+		return &Error{
+			Kind:   KindCanceled,
+			Detail: "The request was canceled by the client.",
+			Err:    err,
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &Error{
+			Kind:   KindTimeout,
+			Detail: "The request took too long to complete.",
+			Err:    err,
+		}
+	case isUnavailable(err):
+		return &Error{
+			Kind:   KindUnavailable,
+			Detail: "The database is currently unavailable, please try again later.",
+			Err:    err,
+		}
+	}
+
+	return &Error{
+		Kind:   KindInternal,
+		Detail: "An unexpected error occurred.",
+		Err:    err,
+	}
+}
+
+var keyPattern = regexp.MustCompile(`^Key \(([^)]+)\)=`)
+
+func (c *crud[T]) notFound(entityId any, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &NotFoundError{
+			Entity: c.model.schema.Name,
+			Id:     entityId,
+		}
+	}
+
+	return c.translate(err)
+}
+
+func (c *crud[T]) affected(entityId any, result *gorm.DB) error {
+	if result.Error != nil {
+		return c.translate(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return &NotFoundError{
+			Entity: c.model.schema.Name,
+			Id:     entityId,
+		}
+	}
+
+	return nil
+}
+
+func (c *crud[T]) translate(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &Error{
+			Kind:   KindTimeout,
+			Detail: fmt.Sprintf("The %s query took too long to complete.", strings.ToLower(c.model.schema.Name)),
+			Err:    err,
+		}
+	}
+
+	if isUnavailable(err) {
+		return &Error{
+			Kind:   KindUnavailable,
+			Detail: "The database is currently unavailable, please try again later.",
+			Err:    err,
+		}
+	}
+
+	var pgError *pgconn.PgError
+
+	if !errors.As(err, &pgError) {
+		return err
+	}
+
+	name := strings.ToLower(c.model.schema.Name)
+
+	switch {
+	case pgError.Code == "23505":
+		fields := c.model.jsonNames(keyColumns(pgError.Detail))
+
+		if len(fields) == 0 {
+			return &Error{
+				Kind:   KindConflict,
+				Detail: fmt.Sprintf("A %s with the same unique values already exists.", name),
+				Err:    err,
+			}
+		}
+
+		return &Error{
+			Kind:   KindConflict,
+			Detail: fmt.Sprintf("A %s with this %s already exists.", name, strings.Join(fields, " and ")),
+			Err:    err,
+		}
+	case pgError.Code == "23503":
+		if pgError.TableName == c.model.schema.Table {
+			return &Error{
+				Kind:   KindConflict,
+				Detail: fmt.Sprintf("The %s references a related record that does not exist.", name),
+				Err:    err,
+			}
+		}
+
+		return &Error{
+			Kind:   KindConflict,
+			Detail: fmt.Sprintf("The %s is still referenced by other records.", name),
+			Err:    err,
+		}
+	case pgError.Code == "23502", pgError.Code == "23514":
+		path := "/"
+		rule := "check"
+		message := fmt.Sprintf("This value violates the %s constraint.", pgError.ConstraintName)
+
+		if pgError.Code == "23502" {
+			rule = "required"
+			message = "This field is required."
+		}
+
+		if fields := c.model.jsonNames([]string{pgError.ColumnName}); len(fields) > 0 {
+			path = "/" + fields[0]
+		}
+
+		return &Error{
+			Kind:   KindValidation,
+			Detail: fmt.Sprintf("The %s payload is invalid.", name),
+			Errors: []FieldError{
+				{
+					Path:    path,
+					Rule:    rule,
+					Message: message,
+				},
+			},
+			Err: err,
+		}
+	}
+
+	return err
+}
+
+func isUnavailable(err error) bool {
+	var connectError *pgconn.ConnectError
+
+	if errors.As(err, &connectError) || errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+
+	var pgError *pgconn.PgError
+
+	if !errors.As(err, &pgError) {
+		return false
+	}
+
+	return strings.HasPrefix(pgError.Code, "08") || strings.HasPrefix(pgError.Code, "53") || strings.HasPrefix(pgError.Code, "57P")
+}
+
+func keyColumns(detail string) []string {
+	match := keyPattern.FindStringSubmatch(detail)
+
+	if match == nil {
+		return nil
+	}
+
+	columns := []string{}
+
+	for _, column := range strings.Split(match[1], ",") {
+		columns = append(columns, strings.Trim(strings.TrimSpace(column), `"`))
+	}
+
+	return columns
+}
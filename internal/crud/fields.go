@@ -0,0 +1,248 @@
+package crud
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm/schema"
+)
+
+type expansion struct {
+	Json     string
+	Relation string
+}
+
+func (m *model) parseFields(value string) ([]string, []string, error) {
+	if value == "" {
+		return nil, nil, nil
+	}
+
+	names := []string{}
+	columns := []string{}
+
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+
+		if name == "" {
+			continue
+		}
+
+		field, exists := m.field(name)
+
+		if !exists {
+			return nil, nil, fmt.Errorf("unknown field %q", name)
+		}
+
+		names = append(names, field.Json)
+		columns = append(columns, field.Column)
+	}
+
+	return names, columns, nil
+}
+
+func (m *model) parseExpand(value string) ([]expansion, error) {
+	expansions := []expansion{}
+
+	if value == "" {
+		return expansions, nil
+	}
+
+	for _, path := range strings.Split(value, ",") {
+		path = strings.TrimSpace(path)
+
+		if path == "" {
+			continue
+		}
+
+		current := m.schema
+		relations := []string{}
+
+		for _, name := range strings.Split(path, ".") {
+			relationship := relationByJson(current, name)
+
+			if relationship == nil {
+				return nil, fmt.Errorf("unknown relation %q", path)
+			}
+
+			relations = append(relations, relationship.Name)
+			current = relationship.FieldSchema
+		}
+
+		expansions = append(expansions, expansion{
+			Json:     strings.Split(path, ".")[0],
+			Relation: strings.Join(relations, "."),
+		})
+	}
+
+	return expansions, nil
+}
+
+func (m *model) requiredColumns(expansions []expansion) []string {
+	columns := []string{}
+
+	for _, field := range m.schema.PrimaryFields {
+		columns = append(columns, field.DBName)
+	}
+
+	if _, exists := m.schema.FieldsByDBName["created_at"]; exists {
+		columns = append(columns, "created_at")
+	}
+
+	if field := m.concurrencyField(); field != nil && field.DBName != "created_at" {
+		columns = append(columns, field.DBName)
+	}
+
+	for _, expansion := range expansions {
+		relationship := m.schema.Relationships.Relations[strings.Split(expansion.Relation, ".")[0]]
+
+		for _, reference := range relationship.References {
+			if reference.OwnPrimaryKey {
+				columns = append(columns, reference.PrimaryKey.DBName)
+			} else if reference.ForeignKey.Schema == m.schema {
+				columns = append(columns, reference.ForeignKey.DBName)
+			}
+		}
+	}
+
+	return columns
+}
+
+func (m *model) expandable() []string {
+	names := []string{}
+
+	for _, relationship := range m.schema.Relationships.Relations {
+		if name := jsonName(relationship.Field); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+func (c *crudApi[T]) parseSelection(ctx *fiber.Ctx, query *Query) error {
+	names, columns, err := c.model.parseFields(ctx.Query("fields"))
+
+	if err != nil {
+		return err
+	}
+
+	expansions, err := c.model.parseExpand(ctx.Query("expand"))
+
+	if err != nil {
+		return err
+	}
+
+	query.Expand = []string{}
+
+	for _, expansion := range expansions {
+		query.Expand = append(query.Expand, expansion.Relation)
+
+		if names != nil {
+			names = append(names, expansion.Json)
+		}
+	}
+
+	if columns != nil {
+		for _, column := range c.model.requiredColumns(expansions) {
+			if !slices.Contains(columns, column) {
+				columns = append(columns, column)
+			}
+		}
+	}
+
+	query.Fields = names
+	query.Columns = columns
+
+	return nil
+}
+
+func project(entity any, fields []string) (any, error) {
+	if fields == nil {
+		return entity, nil
+	}
+
+	data, err := json.Marshal(entity)
+
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]any{}
+
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	projected := map[string]any{}
+
+	for _, field := range fields {
+		if value, exists := values[field]; exists {
+			projected[field] = value
+		}
+	}
+
+	return projected, nil
+}
+
+func relationByJson(current *schema.Schema, name string) *schema.Relationship {
+	for _, relationship := range current.Relationships.Relations {
+		if jsonName(relationship.Field) == name {
+			return relationship
+		}
+	}
+
+	return nil
+}
+
+func (m *model) selectionParameters() []*openapi3.ParameterRef {
+	fieldNames := []any{}
+
+	for _, name := range m.order {
+		fieldNames = append(fieldNames, name)
+	}
+
+	fields := openapi3.NewQueryParameter("fields").
+		WithDescription("Comma separated list of fields to return, e.g. fields=id,name.").
+		WithSchema(openapi3.NewArraySchema().
+			WithItems(openapi3.NewStringSchema().WithEnum(fieldNames...)))
+
+	fields.Style = openapi3.SerializationForm
+	fields.Explode = openapi3.BoolPtr(false)
+
+	relations := m.expandable()
+
+	expandItems := openapi3.NewStringSchema()
+	expandDescription := "This entity has no expandable relations."
+
+	if len(relations) > 0 {
+		relationNames := []any{}
+
+		for _, name := range relations {
+			relationNames = append(relationNames, name)
+		}
+
+		expandItems = expandItems.WithEnum(relationNames...)
+		expandDescription = fmt.Sprintf(
+			"Comma separated list of relations to include, nested relations are separated by a dot. Expandable relations are %s.",
+			strings.Join(relations, ", "),
+		)
+	}
+
+	expand := openapi3.NewQueryParameter("expand").
+		WithDescription(expandDescription).
+		WithSchema(openapi3.NewArraySchema().WithItems(expandItems))
+
+	expand.Style = openapi3.SerializationForm
+	expand.Explode = openapi3.BoolPtr(false)
+
+	return []*openapi3.ParameterRef{
+		{Value: fields},
+		{Value: expand},
+	}
+}
@@ -0,0 +1,302 @@
+package crud
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm/clause"
+)
+
+type Operator string
+
+const (
+	Equal              Operator = "eq"
+	NotEqual           Operator = "ne"
+	GreaterThan        Operator = "gt"
+	GreaterThanOrEqual Operator = "gte"
+	LessThan           Operator = "lt"
+	LessThanOrEqual    Operator = "lte"
+	Contains           Operator = "contains"
+	StartsWith         Operator = "startsWith"
+	EndsWith           Operator = "endsWith"
+	In                 Operator = "in"
+	NotIn              Operator = "nin"
+	IsNull             Operator = "null"
+)
+
+var Operators = []Operator{
+	Equal,
+	NotEqual,
+	GreaterThan,
+	GreaterThanOrEqual,
+	LessThan,
+	LessThanOrEqual,
+	Contains,
+	StartsWith,
+	EndsWith,
+	In,
+	NotIn,
+	IsNull,
+}
+
+type Filter struct {
+	Column   string
+	Operator Operator
+	Value    any
+}
+
+var filterKeyPattern = regexp.MustCompile(`^filter\[([^\]]+)\](?:\[([^\]]+)\])?$`)
+
+func (m *model) parseFilters(ctx *fiber.Ctx) ([]Filter, error) {
+	filters := []Filter{}
+
+	var parseErr error
+
+	ctx.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		if parseErr != nil || !strings.HasPrefix(string(key), "filter") {
+			return
+		}
+
+		matches := filterKeyPattern.FindStringSubmatch(string(key))
+
+		if matches == nil {
+			parseErr = fmt.Errorf("invalid filter parameter %q", string(key))
+			return
+		}
+
+		field, exists := m.field(matches[1])
+
+		if !exists {
+			parseErr = fmt.Errorf("unknown filter field %q", matches[1])
+			return
+		}
+
+		operator := Equal
+
+		if matches[2] != "" {
+			operator = Operator(matches[2])
+		}
+
+		filter, err := newFilter(field, operator, string(value))
+
+		if err != nil {
+			parseErr = err
+			return
+		}
+
+		filters = append(filters, filter)
+	})
+
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	return filters, nil
+}
+
+func newFilter(field *modelField, operator Operator, raw string) (Filter, error) {
+	filter := Filter{
+		Column:   field.Column,
+		Operator: operator,
+	}
+
+	switch operator {
+	case Equal, NotEqual, GreaterThan, GreaterThanOrEqual, LessThan, LessThanOrEqual:
+		value, err := convertFilterValue(field, raw)
+
+		if err != nil {
+			return filter, err
+		}
+
+		filter.Value = value
+	case Contains, StartsWith, EndsWith:
+		if field.Field.IndirectFieldType.Kind() != reflect.String {
+			return filter, fmt.Errorf("operator %q is only supported on text fields, %q is not one", operator, field.Json)
+		}
+
+		filter.Value = raw
+	case In, NotIn:
+		values := []any{}
+
+		for _, part := range strings.Split(raw, ",") {
+			value, err := convertFilterValue(field, strings.TrimSpace(part))
+
+			if err != nil {
+				return filter, err
+			}
+
+			values = append(values, value)
+		}
+
+		filter.Value = values
+	case IsNull:
+		value, err := strconv.ParseBool(raw)
+
+		if err != nil {
+			return filter, fmt.Errorf("operator %q on %q expects true or false", operator, field.Json)
+		}
+
+		filter.Value = value
+	default:
+		return filter, fmt.Errorf("unknown filter operator %q on %q", operator, field.Json)
+	}
+
+	return filter, nil
+}
+
+func convertFilterValue(field *modelField, raw string) (any, error) {
+	fieldType := field.Field.IndirectFieldType
+
+	invalid := fmt.Errorf("invalid value %q for filter field %q", raw, field.Json)
+
+	if fieldType == reflect.TypeOf(time.Time{}) {
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+			if value, err := time.Parse(layout, raw); err == nil {
+				return value, nil
+			}
+		}
+
+		return nil, invalid
+	}
+
+	if reflect.PointerTo(fieldType).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()) {
+		value := reflect.New(fieldType)
+
+		if err := value.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw)); err != nil {
+			return nil, invalid
+		}
+
+		return value.Elem().Interface(), nil
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String:
+		return raw, nil
+	case reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+
+		if err != nil {
+			return nil, invalid
+		}
+
+		return value, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(raw, 10, 64)
+
+		if err != nil {
+			return nil, invalid
+		}
+
+		return value, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseUint(raw, 10, 64)
+
+		if err != nil {
+			return nil, invalid
+		}
+
+		return value, nil
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(raw, 64)
+
+		if err != nil {
+			return nil, invalid
+		}
+
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("filtering is not supported on field %q", field.Json)
+}
+
+func (f Filter) Expression() clause.Expression {
+	column := clause.Column{Name: f.Column}
+
+	switch f.Operator {
+	case NotEqual:
+		return clause.Neq{Column: column, Value: f.Value}
+	case GreaterThan:
+		return clause.Gt{Column: column, Value: f.Value}
+	case GreaterThanOrEqual:
+		return clause.Gte{Column: column, Value: f.Value}
+	case LessThan:
+		return clause.Lt{Column: column, Value: f.Value}
+	case LessThanOrEqual:
+		return clause.Lte{Column: column, Value: f.Value}
+	case Contains:
+		return clause.Expr{SQL: "? ILIKE ?", Vars: []any{column, "%" + escapeLike(f.Value.(string)) + "%"}}
+	case StartsWith:
+		return clause.Expr{SQL: "? ILIKE ?", Vars: []any{column, escapeLike(f.Value.(string)) + "%"}}
+	case EndsWith:
+		return clause.Expr{SQL: "? ILIKE ?", Vars: []any{column, "%" + escapeLike(f.Value.(string))}}
+	case In:
+		return clause.IN{Column: column, Values: f.Value.([]any)}
+	case NotIn:
+		return clause.Not(clause.IN{Column: column, Values: f.Value.([]any)})
+	case IsNull:
+		if f.Value.(bool) {
+			return clause.Eq{Column: column, Value: nil}
+		}
+
+		return clause.Neq{Column: column, Value: nil}
+	}
+
+	return clause.Eq{Column: column, Value: f.Value}
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+func (m *model) filterParameter() *openapi3.ParameterRef {
+	properties := map[string]*openapi3.Schema{}
+
+	for _, name := range m.order {
+		field := m.fields[name]
+
+		operators := map[string]*openapi3.Schema{}
+
+		for _, operator := range Operators {
+			if field.Field.IndirectFieldType.Kind() != reflect.String {
+				if operator == Contains || operator == StartsWith || operator == EndsWith {
+					continue
+				}
+			}
+
+			operators[string(operator)] = openapi3.NewStringSchema()
+		}
+
+		properties[name] = openapi3.NewObjectSchema().WithProperties(operators)
+	}
+
+	parameter := openapi3.NewQueryParameter("filter").
+		WithDescription(fmt.Sprintf(
+			"Filters the results, e.g. filter[name][contains]=bob. Supported operators are %s, a field without an operator is compared with eq.",
+			strings.Join(operatorNames(), ", "),
+		)).
+		WithSchema(openapi3.NewObjectSchema().WithProperties(properties))
+
+	parameter.Style = openapi3.SerializationDeepObject
+	parameter.Explode = openapi3.BoolPtr(true)
+
+	return &openapi3.ParameterRef{
+		Value: parameter,
+	}
+}
+
+func operatorNames() []string {
+	names := []string{}
+
+	for _, operator := range Operators {
+		names = append(names, string(operator))
+	}
+
+	return names
+}
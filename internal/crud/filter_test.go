@@ -0,0 +1,230 @@
+package crud
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFilters(t *testing.T) {
+	model := parseModel[testWidget]()
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []Filter
+		wantErr string
+	}{
+		{
+			name:  "no filters",
+			query: "limit=5",
+			want:  []Filter{},
+		},
+		{
+			name:  "equal is the default operator",
+			query: "filter[name]=widget",
+			want:  []Filter{{Column: "name", Operator: Equal, Value: "widget"}},
+		},
+		{
+			name:  "explicit operator",
+			query: "filter[count][gte]=3",
+			want:  []Filter{{Column: "count", Operator: GreaterThanOrEqual, Value: int64(3)}},
+		},
+		{
+			name:  "float value",
+			query: "filter[price][lt]=9.5",
+			want:  []Filter{{Column: "price", Operator: LessThan, Value: 9.5}},
+		},
+		{
+			name:  "bool value",
+			query: "filter[active][ne]=true",
+			want:  []Filter{{Column: "active", Operator: NotEqual, Value: true}},
+		},
+		{
+			name:  "date value",
+			query: "filter[createdAt][gt]=2024-01-02",
+			want:  []Filter{{Column: "created_at", Operator: GreaterThan, Value: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}},
+		},
+		{
+			name:  "in list",
+			query: "filter[count][in]=1, 2,3",
+			want:  []Filter{{Column: "count", Operator: In, Value: []any{int64(1), int64(2), int64(3)}}},
+		},
+		{
+			name:  "null check",
+			query: "filter[deletedAt][null]=false",
+			want:  []Filter{{Column: "deleted_at", Operator: IsNull, Value: false}},
+		},
+		{
+			name:  "text operator",
+			query: "filter[name][contains]=50%",
+			want:  []Filter{{Column: "name", Operator: Contains, Value: "50%"}},
+		},
+		{
+			name:  "multiple filters",
+			query: "filter[name][startsWith]=a&filter[count][lte]=10",
+			want: []Filter{
+				{Column: "name", Operator: StartsWith, Value: "a"},
+				{Column: "count", Operator: LessThanOrEqual, Value: int64(10)},
+			},
+		},
+		{
+			name:    "malformed key",
+			query:   "filter[name",
+			wantErr: "invalid filter parameter",
+		},
+		{
+			name:    "unknown field",
+			query:   "filter[colour]=red",
+			wantErr: "unknown filter field",
+		},
+		{
+			name:    "unknown operator",
+			query:   "filter[name][like]=a",
+			wantErr: "unknown filter operator",
+		},
+		{
+			name:    "invalid number",
+			query:   "filter[count]=many",
+			wantErr: "invalid value",
+		},
+		{
+			name:    "text operator on a number",
+			query:   "filter[count][contains]=1",
+			wantErr: "only supported on text fields",
+		},
+		{
+			name:    "null expects a bool",
+			query:   "filter[deletedAt][null]=maybe",
+			wantErr: "expects true or false",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filters, err := model.parseFilters(newRequestCtx(t, "/widgets?"+test.query))
+
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", test.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(filters, test.want) {
+				t.Fatalf("expected %#v, got %#v", test.want, filters)
+			}
+		})
+	}
+}
+
+func TestFilterExpression(t *testing.T) {
+	db := newDryRun(t)
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		wantSQL  string
+		wantVars []any
+	}{
+		{
+			name:     "equal",
+			filter:   Filter{Column: "name", Operator: Equal, Value: "a"},
+			wantSQL:  `"name" = $1`,
+			wantVars: []any{"a"},
+		},
+		{
+			name:     "not equal",
+			filter:   Filter{Column: "name", Operator: NotEqual, Value: "a"},
+			wantSQL:  `"name" <> $1`,
+			wantVars: []any{"a"},
+		},
+		{
+			name:     "greater than",
+			filter:   Filter{Column: "count", Operator: GreaterThan, Value: int64(1)},
+			wantSQL:  `"count" > $1`,
+			wantVars: []any{int64(1)},
+		},
+		{
+			name:     "greater than or equal",
+			filter:   Filter{Column: "count", Operator: GreaterThanOrEqual, Value: int64(1)},
+			wantSQL:  `"count" >= $1`,
+			wantVars: []any{int64(1)},
+		},
+		{
+			name:     "less than",
+			filter:   Filter{Column: "count", Operator: LessThan, Value: int64(1)},
+			wantSQL:  `"count" < $1`,
+			wantVars: []any{int64(1)},
+		},
+		{
+			name:     "less than or equal",
+			filter:   Filter{Column: "count", Operator: LessThanOrEqual, Value: int64(1)},
+			wantSQL:  `"count" <= $1`,
+			wantVars: []any{int64(1)},
+		},
+		{
+			name:     "contains escapes wildcards",
+			filter:   Filter{Column: "name", Operator: Contains, Value: `50%_\`},
+			wantSQL:  `"name" ILIKE $1`,
+			wantVars: []any{`%50\%\_\\%`},
+		},
+		{
+			name:     "starts with",
+			filter:   Filter{Column: "name", Operator: StartsWith, Value: "a"},
+			wantSQL:  `"name" ILIKE $1`,
+			wantVars: []any{"a%"},
+		},
+		{
+			name:     "ends with",
+			filter:   Filter{Column: "name", Operator: EndsWith, Value: "a"},
+			wantSQL:  `"name" ILIKE $1`,
+			wantVars: []any{"%a"},
+		},
+		{
+			name:     "in",
+			filter:   Filter{Column: "count", Operator: In, Value: []any{int64(1), int64(2)}},
+			wantSQL:  `"count" IN ($1,$2)`,
+			wantVars: []any{int64(1), int64(2)},
+		},
+		{
+			name:     "not in",
+			filter:   Filter{Column: "count", Operator: NotIn, Value: []any{int64(1), int64(2)}},
+			wantSQL:  `"count" NOT IN ($1,$2)`,
+			wantVars: []any{int64(1), int64(2)},
+		},
+		{
+			name:    "is null",
+			filter:  Filter{Column: "deleted_at", Operator: IsNull, Value: true},
+			wantSQL: `"deleted_at" IS NULL`,
+		},
+		{
+			name:    "is not null",
+			filter:  Filter{Column: "deleted_at", Operator: IsNull, Value: false},
+			wantSQL: `"deleted_at" IS NOT NULL`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			statement := db.Unscoped().Where(test.filter.Expression()).Find(&[]testWidget{}).Statement
+
+			sql := statement.SQL.String()
+			want := `SELECT * FROM "test_widgets" WHERE ` + test.wantSQL
+
+			if sql != want {
+				t.Fatalf("expected %q, got %q", want, sql)
+			}
+
+			if len(test.wantVars) > 0 && !reflect.DeepEqual(statement.Vars, test.wantVars) {
+				t.Fatalf("expected vars %#v, got %#v", test.wantVars, statement.Vars)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package crud
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/connor-davis/dynamic-crud/internal/auth"
+	"github.com/connor-davis/dynamic-crud/internal/routing"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type testWidget struct {
+	Id        uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid"`
+	Name      string         `json:"name" gorm:"type:text;unique"`
+	Count     int            `json:"count"`
+	Price     float64        `json:"price"`
+	Active    bool           `json:"active"`
+	CreatedAt time.Time      `json:"createdAt"`
+	DeletedAt gorm.DeletedAt `json:"deletedAt"`
+}
+
+type dryRunPool struct{}
+
+func (dryRunPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, nil
+}
+
+func (dryRunPool) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return nil, nil
+}
+
+func (dryRunPool) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return nil, nil
+}
+
+func (dryRunPool) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return nil
+}
+
+type dryRunStorage struct {
+	db *gorm.DB
+}
+
+func (s dryRunStorage) Database() *gorm.DB {
+	return s.db
+}
+
+func (s dryRunStorage) Migrate() error {
+	return nil
+}
+
+func newDryRun(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: dryRunPool{}}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		Logger:                 logger.Discard,
+	})
+
+	if err != nil {
+		t.Fatalf("failed to open dry run database: %v", err)
+	}
+
+	return db
+}
+
+func newRequestCtx(t *testing.T, uri string) *fiber.Ctx {
+	t.Helper()
+
+	app := fiber.New()
+	ctx := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	t.Cleanup(func() {
+		app.ReleaseCtx(ctx)
+	})
+
+	ctx.Request().SetRequestURI(uri)
+
+	return ctx
+}
+
+func newTestApi[T any](t *testing.T, crud Crud[T]) *crudApi[T] {
+	t.Helper()
+
+	api := NewCrudApi[T](dryRunStorage{newDryRun(t)}).(*crudApi[T])
+
+	if crud != nil {
+		api.crud = crud
+	}
+
+	return api
+}
+
+func newTestApp(routes ...routing.Route) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+
+	for _, route := range routes {
+		path := regexp.MustCompile(`\{([^}]+)\}`).ReplaceAllString(route.Path, ":$1")
+
+		app.Add(string(route.Method), path, append(route.Middlewares, route.Handler)...)
+	}
+
+	return app
+}
+
+type claimsAuthenticator struct {
+	claims *auth.Claims
+}
+
+func (a claimsAuthenticator) Authenticate(ctx *fiber.Ctx) (bool, error) {
+	if a.claims == nil {
+		return false, nil
+	}
+
+	auth.SetClaims(ctx, a.claims)
+
+	return true, nil
+}
+
+func (a claimsAuthenticator) SecurityScheme() (string, *openapi3.SecurityScheme) {
+	return "TestAuth", openapi3.NewJWTSecurityScheme()
+}
@@ -0,0 +1,77 @@
+package crud
+
+import "context"
+
+type Hook[T any] func(ctx context.Context, entity *T) error
+
+type hooks[T any] struct {
+	beforeCreate []Hook[T]
+	afterCreate  []Hook[T]
+	beforeUpdate []Hook[T]
+	afterUpdate  []Hook[T]
+	beforeDelete []Hook[T]
+	afterDelete  []Hook[T]
+	afterFind    []Hook[T]
+}
+
+func runHooks[T any](ctx context.Context, hooks []Hook[T], entity *T) error {
+	for _, hook := range hooks {
+		if err := hook(ctx, entity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runHooksAll[T any](ctx context.Context, hooks []Hook[T], entities []T) error {
+	for index := range entities {
+		if err := runHooks(ctx, hooks, &entities[index]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *crudApi[T]) BeforeCreate(hook Hook[T]) CrudApi[T] {
+	c.hooks.beforeCreate = append(c.hooks.beforeCreate, hook)
+
+	return c
+}
+
+func (c *crudApi[T]) AfterCreate(hook Hook[T]) CrudApi[T] {
+	c.hooks.afterCreate = append(c.hooks.afterCreate, hook)
+
+	return c
+}
+
+func (c *crudApi[T]) BeforeUpdate(hook Hook[T]) CrudApi[T] {
+	c.hooks.beforeUpdate = append(c.hooks.beforeUpdate, hook)
+
+	return c
+}
+
+func (c *crudApi[T]) AfterUpdate(hook Hook[T]) CrudApi[T] {
+	c.hooks.afterUpdate = append(c.hooks.afterUpdate, hook)
+
+	return c
+}
+
+func (c *crudApi[T]) BeforeDelete(hook Hook[T]) CrudApi[T] {
+	c.hooks.beforeDelete = append(c.hooks.beforeDelete, hook)
+
+	return c
+}
+
+func (c *crudApi[T]) AfterDelete(hook Hook[T]) CrudApi[T] {
+	c.hooks.afterDelete = append(c.hooks.afterDelete, hook)
+
+	return c
+}
+
+func (c *crudApi[T]) AfterFind(hook Hook[T]) CrudApi[T] {
+	c.hooks.afterFind = append(c.hooks.afterFind, hook)
+
+	return c
+}
@@ -0,0 +1,289 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/connor-davis/dynamic-crud/internal/routing/schemas"
+	"github.com/google/uuid"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+type modelField struct {
+	Name   string
+	Json   string
+	Column string
+	Field  *schema.Field
+}
+
+type model struct {
+	schema *schema.Schema
+	fields map[string]*modelField
+	order  []string
+}
+
+func parseModel[T any]() *model {
+	parsed, err := schema.Parse(new(T), &sync.Map{}, schema.NamingStrategy{})
+
+	if err != nil {
+		panic("failed to parse model: " + err.Error())
+	}
+
+	m := &model{
+		schema: parsed,
+		fields: map[string]*modelField{},
+		order:  []string{},
+	}
+
+	for _, field := range parsed.Fields {
+		if field.DBName == "" {
+			continue
+		}
+
+		name := jsonName(field)
+
+		if name == "" {
+			continue
+		}
+
+		m.fields[name] = &modelField{
+			Name:   field.Name,
+			Json:   name,
+			Column: field.DBName,
+			Field:  field,
+		}
+
+		m.order = append(m.order, name)
+	}
+
+	return m
+}
+
+func (m *model) field(name string) (*modelField, bool) {
+	field, exists := m.fields[name]
+
+	return field, exists
+}
+
+func jsonName(field *schema.Field) string {
+	tag, exists := field.Tag.Lookup("json")
+
+	if !exists {
+		return field.Name
+	}
+
+	name := strings.Split(tag, ",")[0]
+
+	if name == "-" {
+		return ""
+	}
+
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
+
+func (m *model) readOnlyColumns() []string {
+	columns := []string{}
+
+	for _, field := range m.schema.Fields {
+		if field.DBName == "" || field.AutoUpdateTime > 0 {
+			continue
+		}
+
+		if field.PrimaryKey || field.AutoCreateTime > 0 || !field.Updatable || schemas.IsReadOnly(field.StructField) {
+			columns = append(columns, field.DBName)
+		}
+	}
+
+	return columns
+}
+
+func (m *model) writableColumns() []string {
+	readOnly := m.readOnlyColumns()
+	columns := []string{}
+
+	for _, field := range m.schema.Fields {
+		if field.DBName != "" && field.AutoUpdateTime == 0 && !slices.Contains(readOnly, field.DBName) {
+			columns = append(columns, field.DBName)
+		}
+	}
+
+	return columns
+}
+
+func (m *model) autoUpdateColumns() []string {
+	columns := []string{}
+
+	for _, field := range m.schema.Fields {
+		if field.DBName != "" && field.AutoUpdateTime > 0 {
+			columns = append(columns, field.DBName)
+		}
+	}
+
+	return columns
+}
+
+func (m *model) parseId(value string) (any, error) {
+	field := m.schema.PrioritizedPrimaryField
+
+	if field == nil {
+		return value, nil
+	}
+
+	switch {
+	case field.FieldType == reflect.TypeOf(uuid.UUID{}):
+		id, err := uuid.Parse(value)
+
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid %s id", value, strings.ToLower(m.schema.Name))
+		}
+
+		return id, nil
+	case field.DataType == schema.Int || field.DataType == schema.Uint:
+		id, err := strconv.ParseInt(value, 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid %s id", value, strings.ToLower(m.schema.Name))
+		}
+
+		return id, nil
+	}
+
+	return value, nil
+}
+
+func (m *model) setPrimaryKey(entity any, id any) error {
+	if m.schema.PrioritizedPrimaryField == nil {
+		return nil
+	}
+
+	return m.schema.PrioritizedPrimaryField.Set(context.Background(), reflect.ValueOf(entity).Elem(), id)
+}
+
+func (m *model) primaryKey(entity any) any {
+	if m.schema.PrioritizedPrimaryField == nil {
+		return nil
+	}
+
+	value, _ := m.schema.PrioritizedPrimaryField.ValueOf(context.Background(), reflect.ValueOf(entity).Elem())
+
+	return value
+}
+
+func (m *model) deletedAtColumn() string {
+	for _, field := range m.schema.Fields {
+		if field.DBName != "" && field.FieldType == deletedAtType {
+			return field.DBName
+		}
+	}
+
+	return ""
+}
+
+func (m *model) softDeletable() bool {
+	return m.deletedAtColumn() != ""
+}
+
+func (m *model) trashed(entity any) bool {
+	field := m.schema.FieldsByDBName[m.deletedAtColumn()]
+
+	if field == nil {
+		return false
+	}
+
+	value, _ := field.ValueOf(context.Background(), reflect.ValueOf(entity).Elem())
+	deletedAt, ok := value.(gorm.DeletedAt)
+
+	return ok && deletedAt.Valid
+}
+
+func (m *model) jsonNames(columns []string) []string {
+	names := []string{}
+
+	for _, column := range columns {
+		for _, name := range m.order {
+			if m.fields[name].Column == column {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
+}
+
+func (m *model) uniqueKeys() [][]string {
+	keys := [][]string{}
+
+	for _, name := range m.order {
+		if field := m.fields[name]; field.Field.Unique && !field.Field.PrimaryKey {
+			keys = append(keys, []string{name})
+		}
+	}
+
+	for _, index := range m.schema.ParseIndexes() {
+		if index.Class != "UNIQUE" || index.Where != "" {
+			continue
+		}
+
+		key := []string{}
+
+		for _, option := range index.Fields {
+			if name := jsonName(option.Field); name != "" && option.Expression == "" {
+				key = append(key, name)
+			}
+		}
+
+		if len(key) == len(index.Fields) && !slices.ContainsFunc(keys, func(existing []string) bool { return slices.Equal(existing, key) }) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func (m *model) changedColumns(before any, after any) []string {
+	beforeValue := reflect.ValueOf(before).Elem()
+	afterValue := reflect.ValueOf(after).Elem()
+	version := m.versionField()
+	deletedAt := m.deletedAtColumn()
+	columns := []string{}
+
+	for _, field := range m.schema.Fields {
+		if field.DBName == "" || field.PrimaryKey || !field.Updatable || field.AutoUpdateTime > 0 || field == version || field.DBName == deletedAt {
+			continue
+		}
+
+		previous, _ := field.ValueOf(context.Background(), beforeValue)
+		current, _ := field.ValueOf(context.Background(), afterValue)
+
+		if !reflect.DeepEqual(previous, current) {
+			columns = append(columns, field.DBName)
+		}
+	}
+
+	return columns
+}
+
+func mergeColumns(columns []string, extra []string) []string {
+	merged := slices.Clone(columns)
+
+	for _, column := range extra {
+		if !slices.Contains(merged, column) {
+			merged = append(merged, column)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,122 @@
+package crud
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestChangedColumns(t *testing.T) {
+	model := parseModel[testWidget]()
+	base := testWidget{Id: uuid.New(), Name: "widget", Count: 1}
+
+	tests := []struct {
+		name   string
+		change func(widget *testWidget)
+		want   []string
+	}{
+		{
+			name:   "unchanged",
+			change: func(widget *testWidget) {},
+			want:   []string{},
+		},
+		{
+			name: "changed fields",
+			change: func(widget *testWidget) {
+				widget.Count = 2
+				widget.Active = true
+			},
+			want: []string{"count", "active"},
+		},
+		{
+			name: "ignores the primary key and deleted at",
+			change: func(widget *testWidget) {
+				widget.Id = uuid.New()
+				widget.DeletedAt = gorm.DeletedAt{Valid: true}
+			},
+			want: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			after := base
+			test.change(&after)
+
+			if got := model.changedColumns(&base, &after); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestMergeColumns(t *testing.T) {
+	columns := []string{"name"}
+	merged := mergeColumns(columns, []string{"count", "name"})
+
+	if !reflect.DeepEqual(merged, []string{"name", "count"}) {
+		t.Fatalf("expected [name count], got %v", merged)
+	}
+
+	if len(columns) != 1 {
+		t.Fatalf("expected the original columns to be untouched, got %v", columns)
+	}
+}
+
+type testCounter struct {
+	Id    int64 `gorm:"primaryKey"`
+	Count int
+}
+
+type testSetting struct {
+	Key   string `gorm:"primaryKey"`
+	Value string
+}
+
+func TestParseId(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name    string
+		model   *model
+		value   string
+		want    any
+		wantErr string
+	}{
+		{name: "uuid", model: parseModel[testWidget](), value: id.String(), want: id},
+		{name: "uppercase uuid", model: parseModel[testWidget](), value: strings.ToUpper(id.String()), want: id},
+		{name: "malformed uuid", model: parseModel[testWidget](), value: "not-a-uuid", wantErr: `"not-a-uuid" is not a valid testwidget id`},
+		{name: "integer instead of uuid", model: parseModel[testWidget](), value: "42", wantErr: "is not a valid"},
+		{name: "integer", model: parseModel[testCounter](), value: "42", want: int64(42)},
+		{name: "negative integer", model: parseModel[testCounter](), value: "-7", want: int64(-7)},
+		{name: "malformed integer", model: parseModel[testCounter](), value: "4x2", wantErr: `"4x2" is not a valid testcounter id`},
+		{name: "decimal integer", model: parseModel[testCounter](), value: "1.5", wantErr: "is not a valid"},
+		{name: "uuid instead of integer", model: parseModel[testCounter](), value: id.String(), wantErr: "is not a valid"},
+		{name: "string key", model: parseModel[testSetting](), value: "theme", want: "theme"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.model.parseId(test.value)
+
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", test.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != test.want {
+				t.Fatalf("expected %#v, got %#v", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package crud
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Operation string
+
+const (
+	OperationCreate     Operation = "create"
+	OperationUpsert     Operation = "upsert"
+	OperationUpdate     Operation = "update"
+	OperationPatch      Operation = "patch"
+	OperationDelete     Operation = "delete"
+	OperationRestore    Operation = "restore"
+	OperationGetOne     Operation = "getOne"
+	OperationGetAll     Operation = "getAll"
+	OperationTrash      Operation = "trash"
+	OperationCacheStats Operation = "cacheStats"
+)
+
+var Operations = []Operation{
+	OperationCreate,
+	OperationUpsert,
+	OperationUpdate,
+	OperationPatch,
+	OperationDelete,
+	OperationRestore,
+	OperationGetOne,
+	OperationGetAll,
+	OperationTrash,
+	OperationCacheStats,
+}
+
+func (c *crudApi[T]) queryContext(ctx *fiber.Ctx, operation Operation) (context.Context, context.CancelFunc) {
+	timeout, exists := c.timeouts[operation]
+
+	if !exists {
+		timeout = c.timeout
+	}
+
+	if timeout <= 0 {
+		return context.WithCancel(ctx.UserContext())
+	}
+
+	return context.WithTimeout(ctx.UserContext(), timeout)
+}
+
+func operationsOrAll(operations []Operation) []Operation {
+	if len(operations) == 0 {
+		return Operations
+	}
+
+	return operations
+}
+
+func parseTimeout(value string) time.Duration {
+	timeout, err := time.ParseDuration(value)
+
+	if err != nil || timeout < 0 {
+		return 0
+	}
+
+	return timeout
+}
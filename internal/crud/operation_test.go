@@ -0,0 +1,128 @@
+package crud
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type blockingCrud struct {
+	Crud[testWidget]
+	started chan struct{}
+	stopped chan error
+}
+
+func (b *blockingCrud) FindOne(ctx context.Context, entityId any, query *Query, entity *testWidget) error {
+	close(b.started)
+
+	<-ctx.Done()
+
+	b.stopped <- ctx.Err()
+
+	return ctx.Err()
+}
+
+func newBlockingApi(t *testing.T, timeout time.Duration) (*crudApi[testWidget], *blockingCrud) {
+	blocking := &blockingCrud{started: make(chan struct{}), stopped: make(chan error, 1)}
+
+	api := newTestApi[testWidget](t, blocking)
+	api.timeout = timeout
+
+	return api, blocking
+}
+
+func TestRouteTimesOut(t *testing.T) {
+	api, blocking := newBlockingApi(t, 20*time.Millisecond)
+	route := api.GetOneRoute()
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Get("/widgets/:id", append(route.Middlewares, route.Handler)...)
+
+	response, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/widgets/"+uuid.NewString(), nil), 5000)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if response.StatusCode != fiber.StatusGatewayTimeout {
+		t.Fatalf("expected status %d, got %d", fiber.StatusGatewayTimeout, response.StatusCode)
+	}
+
+	if err := <-blocking.stopped; err != context.DeadlineExceeded {
+		t.Fatalf("expected the query to hit its deadline, got %v", err)
+	}
+}
+
+func TestRouteCanceledWhenClientDisconnects(t *testing.T) {
+	api, blocking := newBlockingApi(t, 0)
+	route := api.GetOneRoute()
+	statuses := make(chan int, 1)
+
+	app := fiber.New(fiber.Config{
+		DisableStartupMessage: true,
+		ErrorHandler: func(ctx *fiber.Ctx, err error) error {
+			defer func() {
+				statuses <- ctx.Response().StatusCode()
+			}()
+
+			return ErrorHandler(ctx, err)
+		},
+	})
+
+	app.Get("/widgets/:id", append(route.Middlewares, route.Handler)...)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	go app.Listener(listener)
+
+	t.Cleanup(func() {
+		app.Shutdown()
+	})
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	writer := bufio.NewWriter(conn)
+	fmt.Fprintf(writer, "GET /widgets/%s HTTP/1.1\r\nHost: localhost\r\n\r\n", uuid.NewString())
+	writer.Flush()
+
+	select {
+	case <-blocking.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the query never started")
+	}
+
+	conn.Close()
+
+	select {
+	case err := <-blocking.stopped:
+		if err != context.Canceled {
+			t.Fatalf("expected the query to be canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the query was not canceled after the client disconnected")
+	}
+
+	select {
+	case status := <-statuses:
+		if status != StatusClientClosedRequest {
+			t.Fatalf("expected status %d, got %d", StatusClientClosedRequest, status)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the canceled request was never answered")
+	}
+}
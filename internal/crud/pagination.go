@@ -0,0 +1,141 @@
+package crud
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const DefaultPageSize = 25
+
+const DefaultMaxPageSize = 100
+
+type Cursor struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Id        uuid.UUID `json:"id"`
+}
+
+type Page struct {
+	Total      int64
+	HasMore    bool
+	NextCursor *Cursor
+}
+
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var cursor Cursor
+
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Id == uuid.Nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &cursor, nil
+}
+
+func parsePage(ctx *fiber.Ctx, maxPageSize int, query *Query) error {
+	query.Limit = min(DefaultPageSize, maxPageSize)
+
+	if value := ctx.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+
+		if err != nil || limit < 1 {
+			return fmt.Errorf("limit must be a positive integer")
+		}
+
+		query.Limit = min(limit, maxPageSize)
+	}
+
+	if value := ctx.Query("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+
+		if err != nil || offset < 0 {
+			return fmt.Errorf("offset must be zero or a positive integer")
+		}
+
+		query.Offset = offset
+	}
+
+	if value := ctx.Query("cursor"); value != "" {
+		if query.Offset > 0 {
+			return fmt.Errorf("cursor and offset cannot be combined")
+		}
+
+		cursor, err := DecodeCursor(value)
+
+		if err != nil {
+			return err
+		}
+
+		query.Cursor = cursor
+	}
+
+	return nil
+}
+
+func pageParameters(maxPageSize int) []*openapi3.ParameterRef {
+	return []*openapi3.ParameterRef{
+		{
+			Value: openapi3.NewQueryParameter("limit").
+				WithDescription(fmt.Sprintf("The maximum number of items to return, at most %d.", maxPageSize)).
+				WithSchema(openapi3.NewIntegerSchema().
+					WithMin(1).
+					WithMax(float64(maxPageSize)).
+					WithDefault(min(DefaultPageSize, maxPageSize))),
+		},
+		{
+			Value: openapi3.NewQueryParameter("offset").
+				WithDescription("The number of items to skip. Cannot be combined with cursor.").
+				WithSchema(openapi3.NewIntegerSchema().WithMin(0).WithDefault(0)),
+		},
+		{
+			Value: openapi3.NewQueryParameter("cursor").
+				WithDescription("An opaque cursor taken from nextCursor of a previous page.").
+				WithSchema(openapi3.NewStringSchema()),
+		},
+	}
+}
+
+func (c *crudApi[T]) respondList(ctx *fiber.Ctx, operation Operation, query *Query, page *Page, entities []T) error {
+	items, err := c.presentAll(ctx.UserContext(), entities, query.Fields)
+
+	if err != nil {
+		return err
+	}
+
+	var nextCursor *string
+
+	if page.NextCursor != nil {
+		encoded := page.NextCursor.Encode()
+		nextCursor = &encoded
+	}
+
+	body, err := ctx.App().Config().JSONEncoder(fiber.Map{
+		"items":      items,
+		"total":      page.Total,
+		"nextCursor": nextCursor,
+		"hasMore":    page.HasMore,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.send(ctx, operation, body, "", time.Time{})
+}
@@ -0,0 +1,91 @@
+package crud
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := &Cursor{
+		CreatedAt: time.Date(2024, 5, 6, 7, 8, 9, 123456000, time.UTC),
+		Id:        uuid.MustParse("5f0c6a36-3a8e-4b9b-9a53-6f43c1c1d8a1"),
+	}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !decoded.CreatedAt.Equal(cursor.CreatedAt) || decoded.Id != cursor.Id {
+		t.Fatalf("expected %+v, got %+v", cursor, decoded)
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	encode := func(value string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			value: encode(`{"createdAt":"2024-01-02T03:04:05Z","id":"5f0c6a36-3a8e-4b9b-9a53-6f43c1c1d8a1"}`),
+		},
+		{
+			name:    "empty",
+			value:   "",
+			wantErr: true,
+		},
+		{
+			name:    "not base64",
+			value:   "not a cursor!",
+			wantErr: true,
+		},
+		{
+			name:    "not json",
+			value:   encode("cursor"),
+			wantErr: true,
+		},
+		{
+			name:    "missing id",
+			value:   encode(`{"createdAt":"2024-01-02T03:04:05Z"}`),
+			wantErr: true,
+		},
+		{
+			name:    "invalid id",
+			value:   encode(`{"createdAt":"2024-01-02T03:04:05Z","id":"nope"}`),
+			wantErr: true,
+		},
+		{
+			name:    "invalid time",
+			value:   encode(`{"createdAt":"yesterday","id":"5f0c6a36-3a8e-4b9b-9a53-6f43c1c1d8a1"}`),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cursor, err := DecodeCursor(test.value)
+
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", cursor)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
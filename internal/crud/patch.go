@@ -0,0 +1,418 @@
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+type PatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+func applyMergePatch(target any, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+
+	if !ok {
+		targetObject = map[string]any{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+
+			continue
+		}
+
+		targetObject[key] = applyMergePatch(targetObject[key], value)
+	}
+
+	return targetObject
+}
+
+func applyJSONPatch(document any, operations []PatchOperation) (any, error) {
+	var err error
+
+	for index, operation := range operations {
+		switch operation.Op {
+		case "add":
+			document, err = pointerAdd(document, operation.Path, operation.Value)
+		case "remove":
+			document, _, err = pointerRemove(document, operation.Path)
+		case "replace":
+			document, _, err = pointerRemove(document, operation.Path)
+
+			if err == nil {
+				document, err = pointerAdd(document, operation.Path, operation.Value)
+			}
+		case "move":
+			var value any
+
+			document, value, err = pointerRemove(document, operation.From)
+
+			if err == nil {
+				document, err = pointerAdd(document, operation.Path, value)
+			}
+		case "copy":
+			var value any
+
+			value, err = pointerGet(document, operation.From)
+
+			if err == nil {
+				document, err = pointerAdd(document, operation.Path, deepCopy(value))
+			}
+		case "test":
+			var value any
+
+			value, err = pointerGet(document, operation.Path)
+
+			if err == nil && !reflect.DeepEqual(value, operation.Value) {
+				err = fmt.Errorf("test failed for %q", operation.Path)
+			}
+		default:
+			err = fmt.Errorf("unknown operation %q", operation.Op)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("patch operation %d: %w", index, err)
+		}
+	}
+
+	return document, nil
+}
+
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+
+	for index, token := range tokens {
+		tokens[index] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+
+	return tokens, nil
+}
+
+func pointerGet(document any, pointer string) (any, error) {
+	tokens, err := parsePointer(pointer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	current := document
+
+	for _, token := range tokens {
+		switch node := current.(type) {
+		case map[string]any:
+			value, exists := node[token]
+
+			if !exists {
+				return nil, fmt.Errorf("path %q does not exist", pointer)
+			}
+
+			current = value
+		case []any:
+			index, err := strconv.Atoi(token)
+
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("path %q does not exist", pointer)
+			}
+
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("path %q does not exist", pointer)
+		}
+	}
+
+	return current, nil
+}
+
+func pointerAdd(document any, pointer string, value any) (any, error) {
+	tokens, err := parsePointer(pointer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	parent, err := pointerGet(document, pointerOf(tokens[:len(tokens)-1]))
+
+	if err != nil {
+		return nil, err
+	}
+
+	last := tokens[len(tokens)-1]
+
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+	case []any:
+		index := len(node)
+
+		if last != "-" {
+			index, err = strconv.Atoi(last)
+
+			if err != nil || index < 0 || index > len(node) {
+				return nil, fmt.Errorf("index %q is out of bounds", last)
+			}
+		}
+
+		node = append(node[:index], append([]any{value}, node[index:]...)...)
+
+		return pointerReplace(document, tokens[:len(tokens)-1], node)
+	default:
+		return nil, fmt.Errorf("path %q does not exist", pointer)
+	}
+
+	return document, nil
+}
+
+func pointerRemove(document any, pointer string) (any, any, error) {
+	tokens, err := parsePointer(pointer)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, document, nil
+	}
+
+	parent, err := pointerGet(document, pointerOf(tokens[:len(tokens)-1]))
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	last := tokens[len(tokens)-1]
+
+	switch node := parent.(type) {
+	case map[string]any:
+		value, exists := node[last]
+
+		if !exists {
+			return nil, nil, fmt.Errorf("path %q does not exist", pointer)
+		}
+
+		delete(node, last)
+
+		return document, value, nil
+	case []any:
+		index, err := strconv.Atoi(last)
+
+		if err != nil || index < 0 || index >= len(node) {
+			return nil, nil, fmt.Errorf("index %q is out of bounds", last)
+		}
+
+		value := node[index]
+
+		node = append(node[:index:index], node[index+1:]...)
+
+		document, err = pointerReplace(document, tokens[:len(tokens)-1], node)
+
+		return document, value, err
+	}
+
+	return nil, nil, fmt.Errorf("path %q does not exist", pointer)
+}
+
+func pointerReplace(document any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	parent, err := pointerGet(document, pointerOf(tokens[:len(tokens)-1]))
+
+	if err != nil {
+		return nil, err
+	}
+
+	last := tokens[len(tokens)-1]
+
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+	case []any:
+		index, _ := strconv.Atoi(last)
+		node[index] = value
+	}
+
+	return document, nil
+}
+
+func pointerOf(tokens []string) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	escaped := []string{}
+
+	for _, token := range tokens {
+		escaped = append(escaped, strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+	}
+
+	return "/" + strings.Join(escaped, "/")
+}
+
+func deepCopy(value any) any {
+	data, _ := json.Marshal(value)
+
+	var copied any
+
+	_ = json.Unmarshal(data, &copied)
+
+	return copied
+}
+
+func (c *crudApi[T]) patchEntity(ctx context.Context, mediaType string, body []byte, entity *T) ([]string, []FieldError, error) {
+	data, err := json.Marshal(entity)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	original := map[string]any{}
+
+	if err := json.Unmarshal(data, &original); err != nil {
+		return nil, nil, err
+	}
+
+	hidden := c.hiddenFields(ctx, entity)
+	visible := deepCopy(original).(map[string]any)
+
+	for _, key := range hidden {
+		delete(visible, key)
+	}
+
+	var document any = visible
+
+	switch mediaType {
+	case JSONPatchContentType:
+		operations := []PatchOperation{}
+
+		if err := json.Unmarshal(body, &operations); err != nil {
+			return nil, nil, fmt.Errorf("the request body must be a JSON patch document: %w", err)
+		}
+
+		document, err = applyJSONPatch(document, operations)
+
+		if err != nil {
+			return nil, nil, err
+		}
+	default:
+		var patch any
+
+		if err := json.Unmarshal(body, &patch); err != nil {
+			return nil, nil, fmt.Errorf("the request body must be a JSON merge patch document: %w", err)
+		}
+
+		document = applyMergePatch(document, patch)
+	}
+
+	patched, ok := document.(map[string]any)
+
+	if !ok {
+		return nil, nil, fmt.Errorf("the patched %s must be a JSON object", strings.ToLower(c.name))
+	}
+
+	for _, key := range hidden {
+		if _, exists := patched[key]; !exists {
+			if value, exists := original[key]; exists {
+				patched[key] = deepCopy(value)
+			}
+		}
+	}
+
+	changes := map[string]any{}
+
+	for key := range original {
+		if _, exists := patched[key]; !exists {
+			changes[key] = nil
+		}
+	}
+
+	for key, value := range patched {
+		if !reflect.DeepEqual(original[key], value) {
+			changes[key] = value
+		}
+	}
+
+	if err := c.guardWritableFields(c.update, changes); err != nil {
+		return nil, nil, err
+	}
+
+	payload := map[string]any{}
+
+	for key := range c.update.Properties {
+		if value, exists := changes[key]; exists {
+			payload[key] = value
+		} else if value, exists := original[key]; exists {
+			payload[key] = value
+		}
+	}
+
+	if fieldErrors := validateSchema(c.update, payload); len(fieldErrors) > 0 {
+		return nil, fieldErrors, nil
+	}
+
+	for key, value := range payload {
+		original[key] = value
+
+		if value == nil {
+			delete(original, key)
+		}
+	}
+
+	data, err = json.Marshal(original)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	*entity = *new(T)
+
+	if err := json.Unmarshal(data, entity); err != nil {
+		return nil, nil, fmt.Errorf("the patched %s does not match the %s schema: %w", strings.ToLower(c.name), strings.ToLower(c.name), err)
+	}
+
+	if fieldErrors := validateEntity(entity); len(fieldErrors) > 0 {
+		return nil, fieldErrors, nil
+	}
+
+	columns := []string{}
+
+	for key := range changes {
+		if field, exists := c.model.field(key); exists {
+			columns = append(columns, field.Column)
+		}
+	}
+
+	return columns, nil, nil
+}
@@ -0,0 +1,189 @@
+package crud
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func decodeJSON(t *testing.T, value string) any {
+	t.Helper()
+
+	var decoded any
+
+	if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+		t.Fatalf("invalid JSON %q: %v", value, err)
+	}
+
+	return decoded
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		patch  string
+		want   string
+	}{
+		{name: "replace a value", target: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "add a value", target: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{name: "remove a value", target: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{name: "replace an array", target: `{"a":["b"]}`, patch: `{"a":["c","d"]}`, want: `{"a":["c","d"]}`},
+		{name: "merge nested objects", target: `{"a":{"b":"c","d":"e"}}`, patch: `{"a":{"d":null,"f":"g"}}`, want: `{"a":{"b":"c","f":"g"}}`},
+		{name: "object replaces a scalar", target: `{"a":"b"}`, patch: `{"a":{"c":"d"}}`, want: `{"a":{"c":"d"}}`},
+		{name: "non object patch replaces the target", target: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{name: "empty patch", target: `{"a":"b"}`, patch: `{}`, want: `{"a":"b"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := applyMergePatch(decodeJSON(t, test.target), decodeJSON(t, test.patch))
+
+			if want := decodeJSON(t, test.want); !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected %#v, got %#v", want, got)
+			}
+		})
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		document   string
+		operations string
+		want       string
+		wantErr    string
+	}{
+		{
+			name:       "add a member",
+			document:   `{"a":1}`,
+			operations: `[{"op":"add","path":"/b","value":2}]`,
+			want:       `{"a":1,"b":2}`,
+		},
+		{
+			name:       "add to an array",
+			document:   `{"a":[1,3]}`,
+			operations: `[{"op":"add","path":"/a/1","value":2}]`,
+			want:       `{"a":[1,2,3]}`,
+		},
+		{
+			name:       "append to an array",
+			document:   `{"a":[1]}`,
+			operations: `[{"op":"add","path":"/a/-","value":2}]`,
+			want:       `{"a":[1,2]}`,
+		},
+		{
+			name:       "remove a member",
+			document:   `{"a":1,"b":2}`,
+			operations: `[{"op":"remove","path":"/a"}]`,
+			want:       `{"b":2}`,
+		},
+		{
+			name:       "remove from an array",
+			document:   `{"a":[1,2,3]}`,
+			operations: `[{"op":"remove","path":"/a/0"}]`,
+			want:       `{"a":[2,3]}`,
+		},
+		{
+			name:       "replace a member",
+			document:   `{"a":1}`,
+			operations: `[{"op":"replace","path":"/a","value":"b"}]`,
+			want:       `{"a":"b"}`,
+		},
+		{
+			name:       "move a member",
+			document:   `{"a":{"b":1},"c":{}}`,
+			operations: `[{"op":"move","from":"/a/b","path":"/c/d"}]`,
+			want:       `{"a":{},"c":{"d":1}}`,
+		},
+		{
+			name:       "copy a member",
+			document:   `{"a":{"b":[1]}}`,
+			operations: `[{"op":"copy","from":"/a","path":"/c"},{"op":"add","path":"/c/b/-","value":2}]`,
+			want:       `{"a":{"b":[1]},"c":{"b":[1,2]}}`,
+		},
+		{
+			name:       "escaped pointer",
+			document:   `{"a/b":1,"c~d":2}`,
+			operations: `[{"op":"replace","path":"/a~1b","value":3},{"op":"remove","path":"/c~0d"}]`,
+			want:       `{"a/b":3}`,
+		},
+		{
+			name:       "passing test",
+			document:   `{"a":{"b":[1,"c"]}}`,
+			operations: `[{"op":"test","path":"/a/b","value":[1,"c"]}]`,
+			want:       `{"a":{"b":[1,"c"]}}`,
+		},
+		{
+			name:       "failing test",
+			document:   `{"a":1}`,
+			operations: `[{"op":"test","path":"/a","value":2}]`,
+			wantErr:    "test failed",
+		},
+		{
+			name:       "remove a missing member",
+			document:   `{"a":1}`,
+			operations: `[{"op":"remove","path":"/b"}]`,
+			wantErr:    "patch operation 0",
+		},
+		{
+			name:       "replace a missing member",
+			document:   `{"a":1}`,
+			operations: `[{"op":"replace","path":"/b","value":2}]`,
+			wantErr:    "patch operation 0",
+		},
+		{
+			name:       "array index out of range",
+			document:   `{"a":[1]}`,
+			operations: `[{"op":"add","path":"/a/5","value":2}]`,
+			wantErr:    "patch operation 0",
+		},
+		{
+			name:       "invalid pointer",
+			document:   `{"a":1}`,
+			operations: `[{"op":"add","path":"a","value":2}]`,
+			wantErr:    "invalid JSON pointer",
+		},
+		{
+			name:       "unknown operation",
+			document:   `{"a":1}`,
+			operations: `[{"op":"rename","path":"/a"}]`,
+			wantErr:    "unknown operation",
+		},
+		{
+			name:       "reports the failing operation",
+			document:   `{"a":1}`,
+			operations: `[{"op":"add","path":"/b","value":2},{"op":"remove","path":"/c"}]`,
+			wantErr:    "patch operation 1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			operations := []PatchOperation{}
+
+			if err := json.Unmarshal([]byte(test.operations), &operations); err != nil {
+				t.Fatalf("invalid operations: %v", err)
+			}
+
+			got, err := applyJSONPatch(decodeJSON(t, test.document), operations)
+
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", test.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if want := decodeJSON(t, test.want); !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected %#v, got %#v", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package crud
+
+import (
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+)
+
+func preferMinimal(ctx *fiber.Ctx) bool {
+	for _, preference := range strings.Split(ctx.Get("Prefer"), ",") {
+		if strings.EqualFold(strings.ReplaceAll(strings.TrimSpace(preference), " ", ""), "return=minimal") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *crudApi[T]) respond(ctx *fiber.Ctx, operation Operation, status int, entity *T) error {
+	if policy, exists := c.cacheControl[operation]; exists {
+		ctx.Set(fiber.HeaderCacheControl, policy)
+	}
+
+	if preferMinimal(ctx) {
+		ctx.Set("Preference-Applied", "return=minimal")
+
+		if status == fiber.StatusOK {
+			status = fiber.StatusNoContent
+		}
+
+		return ctx.Status(status).Send(nil)
+	}
+
+	item, redacted, err := c.present(ctx.UserContext(), entity, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if !redacted {
+		c.setETag(ctx, entity)
+	}
+
+	return ctx.Status(status).JSON(fiber.Map{
+		"item": item,
+	})
+}
+
+func preferParameter() *openapi3.ParameterRef {
+	return &openapi3.ParameterRef{
+		Value: openapi3.NewHeaderParameter("Prefer").
+			WithDescription("Send return=minimal to receive an empty body instead of the stored entity.").
+			WithSchema(openapi3.NewStringSchema().WithEnum("return=minimal", "return=representation")),
+	}
+}
+
+func locationHeader() *openapi3.HeaderRef {
+	return &openapi3.HeaderRef{
+		Value: &openapi3.Header{
+			Parameter: openapi3.Parameter{
+				Description: "The URL of the created entity.",
+				Schema:      openapi3.NewStringSchema().WithFormat("uri-reference").NewRef(),
+			},
+		},
+	}
+}
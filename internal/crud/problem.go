@@ -0,0 +1,94 @@
+package crud
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/connor-davis/dynamic-crud/internal/routing/schemas"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+const StatusClientClosedRequest = 499
+
+var kindStatus = map[Kind]int{
+	KindBadRequest:           fiber.StatusBadRequest,
+	KindUnauthorized:         fiber.StatusUnauthorized,
+	KindForbidden:            fiber.StatusForbidden,
+	KindNotFound:             fiber.StatusNotFound,
+	KindConflict:             fiber.StatusConflict,
+	KindPreconditionFailed:   fiber.StatusPreconditionFailed,
+	KindPreconditionRequired: fiber.StatusPreconditionRequired,
+	KindUnsupportedMediaType: fiber.StatusUnsupportedMediaType,
+	KindValidation:           fiber.StatusUnprocessableEntity,
+	KindInternal:             fiber.StatusInternalServerError,
+	KindUnavailable:          fiber.StatusServiceUnavailable,
+	KindTimeout:              fiber.StatusGatewayTimeout,
+	KindCanceled:             StatusClientClosedRequest,
+}
+
+func ErrorHandler(ctx *fiber.Ctx, err error) error {
+	problem := newProblem(err)
+	problem.Instance = ctx.OriginalURL()
+
+	if problem.Status >= fiber.StatusInternalServerError {
+		log.Printf("🔥 %s %s failed: %v", ctx.Method(), ctx.OriginalURL(), err)
+	}
+
+	return ctx.Status(problem.Status).JSON(problem, schemas.ProblemContentType)
+}
+
+func newProblem(err error) *Problem {
+	var fiberError *fiber.Error
+
+	if errors.As(err, &fiberError) {
+		for kind, status := range kindStatus {
+			if status == fiberError.Code {
+				return problemOf(kind, fiberError.Message, nil)
+			}
+		}
+
+		return &Problem{
+			Type:   "about:blank",
+			Title:  utils.StatusMessage(fiberError.Code),
+			Status: fiberError.Code,
+			Detail: fiberError.Message,
+		}
+	}
+
+	crudError := asError(err)
+
+	return problemOf(crudError.Kind, crudError.Detail, crudError.Errors)
+}
+
+func problemOf(kind Kind, detail string, fieldErrors []FieldError) *Problem {
+	status, exists := kindStatus[kind]
+
+	if !exists {
+		status = fiber.StatusInternalServerError
+	}
+
+	title := utils.StatusMessage(status)
+
+	if status == StatusClientClosedRequest {
+		title = "Client Closed Request"
+	}
+
+	return &Problem{
+		Type:   fmt.Sprintf("/problems/%s", kind),
+		Title:  title,
+		Status: status,
+		Detail: detail,
+		Errors: fieldErrors,
+	}
+}
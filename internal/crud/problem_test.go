@@ -0,0 +1,155 @@
+package crud
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestNewProblem(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantType   string
+		wantStatus int
+		wantDetail string
+	}{
+		{
+			name:       "bad request",
+			err:        NewError(KindBadRequest, "Bad input."),
+			wantType:   "/problems/bad-request",
+			wantStatus: fiber.StatusBadRequest,
+			wantDetail: "Bad input.",
+		},
+		{
+			name:       "forbidden",
+			err:        NewError(KindForbidden, "No."),
+			wantType:   "/problems/forbidden",
+			wantStatus: fiber.StatusForbidden,
+			wantDetail: "No.",
+		},
+		{
+			name:       "conflict",
+			err:        NewError(KindConflict, "Taken."),
+			wantType:   "/problems/conflict",
+			wantStatus: fiber.StatusConflict,
+			wantDetail: "Taken.",
+		},
+		{
+			name:       "precondition failed",
+			err:        NewError(KindPreconditionFailed, "Stale."),
+			wantType:   "/problems/precondition-failed",
+			wantStatus: fiber.StatusPreconditionFailed,
+			wantDetail: "Stale.",
+		},
+		{
+			name:       "precondition required",
+			err:        NewError(KindPreconditionRequired, "Send If-Match."),
+			wantType:   "/problems/precondition-required",
+			wantStatus: fiber.StatusPreconditionRequired,
+			wantDetail: "Send If-Match.",
+		},
+		{
+			name:       "validation",
+			err:        NewValidationError("Invalid.", []FieldError{{Path: "name", Rule: "required"}}),
+			wantType:   "/problems/validation",
+			wantStatus: fiber.StatusUnprocessableEntity,
+			wantDetail: "Invalid.",
+		},
+		{
+			name:       "wrapped crud error",
+			err:        fmt.Errorf("handler: %w", NewError(KindUnauthorized, "Sign in.")),
+			wantType:   "/problems/unauthorized",
+			wantStatus: fiber.StatusUnauthorized,
+			wantDetail: "Sign in.",
+		},
+		{
+			name:       "not found",
+			err:        &NotFoundError{Entity: "Widget"},
+			wantType:   "/problems/not-found",
+			wantStatus: fiber.StatusNotFound,
+			wantDetail: "The widget was not found.",
+		},
+		{
+			name:       "deadline exceeded",
+			err:        fmt.Errorf("query: %w", context.DeadlineExceeded),
+			wantType:   "/problems/timeout",
+			wantStatus: fiber.StatusGatewayTimeout,
+			wantDetail: "The request took too long to complete.",
+		},
+		{
+			name:       "canceled",
+			err:        fmt.Errorf("query: %w", context.Canceled),
+			wantType:   "/problems/canceled",
+			wantStatus: StatusClientClosedRequest,
+			wantDetail: "The request was canceled by the client.",
+		},
+		{
+			name:       "bad connection",
+			err:        driver.ErrBadConn,
+			wantType:   "/problems/unavailable",
+			wantStatus: fiber.StatusServiceUnavailable,
+		},
+		{
+			name:       "database shutting down",
+			err:        &pgconn.PgError{Code: "57P01"},
+			wantType:   "/problems/unavailable",
+			wantStatus: fiber.StatusServiceUnavailable,
+		},
+		{
+			name:       "unexpected error",
+			err:        errors.New("boom"),
+			wantType:   "/problems/internal",
+			wantStatus: fiber.StatusInternalServerError,
+			wantDetail: "An unexpected error occurred.",
+		},
+		{
+			name:       "unknown kind",
+			err:        NewError(Kind("mystery"), "Huh."),
+			wantType:   "/problems/mystery",
+			wantStatus: fiber.StatusInternalServerError,
+			wantDetail: "Huh.",
+		},
+		{
+			name:       "mapped fiber error",
+			err:        fiber.NewError(fiber.StatusNotFound, "Cannot GET /nowhere"),
+			wantType:   "/problems/not-found",
+			wantStatus: fiber.StatusNotFound,
+			wantDetail: "Cannot GET /nowhere",
+		},
+		{
+			name:       "unmapped fiber error",
+			err:        fiber.NewError(fiber.StatusMethodNotAllowed, "Method Not Allowed"),
+			wantType:   "about:blank",
+			wantStatus: fiber.StatusMethodNotAllowed,
+			wantDetail: "Method Not Allowed",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			problem := newProblem(test.err)
+
+			if problem.Type != test.wantType {
+				t.Fatalf("expected type %q, got %q", test.wantType, problem.Type)
+			}
+
+			if problem.Status != test.wantStatus {
+				t.Fatalf("expected status %d, got %d", test.wantStatus, problem.Status)
+			}
+
+			if test.wantDetail != "" && problem.Detail != test.wantDetail {
+				t.Fatalf("expected detail %q, got %q", test.wantDetail, problem.Detail)
+			}
+
+			if problem.Title == "" {
+				t.Fatal("expected a title")
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package crud
+
+import (
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Query struct {
+	Filters  []Filter
+	Sort     []Sort
+	Fields   []string
+	Columns  []string
+	Expand   []string
+	Limit    int
+	Offset   int
+	Cursor   *Cursor
+	Unscoped bool
+	Lock     bool
+	Scopes   []clause.Expression
+}
+
+func (q *Query) Apply(db *gorm.DB) *gorm.DB {
+	if q == nil {
+		return db
+	}
+
+	for _, filter := range q.Filters {
+		db = db.Where(filter.Expression())
+	}
+
+	for _, scope := range q.Scopes {
+		db = db.Where(scope)
+	}
+
+	return db
+}
+
+func (q *Query) ApplySelection(db *gorm.DB) *gorm.DB {
+	if q == nil {
+		return db
+	}
+
+	if q.Unscoped {
+		db = db.Unscoped()
+	}
+
+	if q.Lock {
+		db = db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	if len(q.Columns) > 0 {
+		db = db.Select(q.Columns)
+	}
+
+	for _, relation := range q.Expand {
+		db = db.Preload(relation)
+	}
+
+	return db
+}
+
+func (q *Query) ApplyPage(db *gorm.DB) *gorm.DB {
+	db = db.Order(orderBy(q.Sort))
+
+	if q.Cursor != nil && isDefaultSort(q.Sort) {
+		db = db.Where("(created_at, id) > (?, ?)", q.Cursor.CreatedAt, q.Cursor.Id)
+	}
+
+	if q.Offset > 0 {
+		db = db.Offset(q.Offset)
+	}
+
+	return db.Limit(q.Limit + 1)
+}
+
+func (c *crudApi[T]) parseListQuery(ctx *fiber.Ctx) (*Query, error) {
+	filters, err := c.model.parseFilters(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.guardFilters(ctx.UserContext(), filters); err != nil {
+		return nil, err
+	}
+
+	query := &Query{Filters: filters}
+
+	if err := c.parseSortQuery(ctx, query); err != nil {
+		return nil, err
+	}
+
+	if err := c.parseSelection(ctx, query); err != nil {
+		return nil, err
+	}
+
+	if err := parsePage(ctx, c.maxPageSize, query); err != nil {
+		return nil, err
+	}
+
+	if query.Cursor != nil && !isDefaultSort(query.Sort) {
+		return nil, fmt.Errorf("cursor pagination is only supported with the default sort order")
+	}
+
+	return query, nil
+}
+
+func (c *crudApi[T]) listParameters() []*openapi3.ParameterRef {
+	parameters := []*openapi3.ParameterRef{
+		c.model.filterParameter(),
+	}
+
+	if len(c.sortable) > 0 {
+		parameters = append(parameters, sortParameter(c.sortable))
+	}
+
+	parameters = append(parameters, c.model.selectionParameters()...)
+	parameters = append(parameters, pageParameters(c.maxPageSize)...)
+	parameters = append(parameters, c.tenantParameters()...)
+
+	return parameters
+}
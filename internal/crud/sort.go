@@ -0,0 +1,140 @@
+package crud
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm/clause"
+)
+
+type Sort struct {
+	Column     string
+	Descending bool
+}
+
+var defaultSort = []Sort{
+	{Column: "created_at"},
+	{Column: "id"},
+}
+
+func (m *model) parseSort(value string, sortable []string) ([]Sort, error) {
+	sorts := []Sort{}
+
+	for _, key := range strings.Split(value, ",") {
+		key = strings.TrimSpace(key)
+
+		if key == "" {
+			continue
+		}
+
+		name := strings.TrimPrefix(key, "-")
+
+		if !slices.Contains(sortable, name) {
+			return nil, fmt.Errorf("sorting by %q is not allowed", name)
+		}
+
+		field, exists := m.field(name)
+
+		if !exists {
+			return nil, fmt.Errorf("unknown sort field %q", name)
+		}
+
+		sorts = append(sorts, Sort{
+			Column:     field.Column,
+			Descending: strings.HasPrefix(key, "-"),
+		})
+	}
+
+	return sorts, nil
+}
+
+func (c *crudApi[T]) parseSortQuery(ctx *fiber.Ctx, query *Query) error {
+	value := ctx.Query("sort")
+
+	if value == "" {
+		query.Sort = c.defaultSort
+
+		return nil
+	}
+
+	sorts, err := c.model.parseSort(value, c.readableSortFields(ctx.UserContext()))
+
+	if err != nil {
+		return err
+	}
+
+	query.Sort = sorts
+
+	return nil
+}
+
+func orderBy(sorts []Sort) clause.OrderBy {
+	if len(sorts) == 0 {
+		sorts = defaultSort
+	}
+
+	columns := []clause.OrderByColumn{}
+	hasId := false
+
+	for _, sort := range sorts {
+		columns = append(columns, clause.OrderByColumn{
+			Column: clause.Column{Name: sort.Column},
+			Desc:   sort.Descending,
+		})
+
+		if sort.Column == "id" {
+			hasId = true
+		}
+	}
+
+	if !hasId {
+		columns = append(columns, clause.OrderByColumn{
+			Column: clause.Column{Name: "id"},
+		})
+	}
+
+	return clause.OrderBy{Columns: columns}
+}
+
+func isDefaultSort(sorts []Sort) bool {
+	if len(sorts) == 0 {
+		return true
+	}
+
+	if len(sorts) > len(defaultSort) {
+		return false
+	}
+
+	for index, sort := range sorts {
+		if sort != defaultSort[index] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortParameter(sortable []string) *openapi3.ParameterRef {
+	keys := []any{}
+
+	for _, name := range sortable {
+		keys = append(keys, name, "-"+name)
+	}
+
+	schema := openapi3.NewArraySchema().
+		WithItems(openapi3.NewStringSchema().WithEnum(keys...))
+
+	parameter := openapi3.NewQueryParameter("sort").
+		WithDescription("Comma separated list of fields to sort by, prefix a field with - to sort descending, e.g. sort=-createdAt,name.").
+		WithSchema(schema)
+
+	parameter.Style = openapi3.SerializationForm
+	parameter.Explode = openapi3.BoolPtr(false)
+
+	return &openapi3.ParameterRef{
+		Value: parameter,
+	}
+}
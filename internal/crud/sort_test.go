@@ -0,0 +1,132 @@
+package crud
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	model := parseModel[testWidget]()
+	sortable := []string{"name", "count", "createdAt"}
+
+	tests := []struct {
+		name    string
+		value   string
+		want    []Sort
+		wantErr string
+	}{
+		{
+			name:  "empty",
+			value: "",
+			want:  []Sort{},
+		},
+		{
+			name:  "ascending",
+			value: "name",
+			want:  []Sort{{Column: "name"}},
+		},
+		{
+			name:  "descending",
+			value: "-count",
+			want:  []Sort{{Column: "count", Descending: true}},
+		},
+		{
+			name:  "multiple fields with blanks",
+			value: "-createdAt, name,,",
+			want:  []Sort{{Column: "created_at", Descending: true}, {Column: "name"}},
+		},
+		{
+			name:    "field not sortable",
+			value:   "price",
+			wantErr: "not allowed",
+		},
+		{
+			name:    "unknown field",
+			value:   "-colour",
+			wantErr: "not allowed",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sorts, err := model.parseSort(test.value, sortable)
+
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", test.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(sorts, test.want) {
+				t.Fatalf("expected %#v, got %#v", test.want, sorts)
+			}
+		})
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	tests := []struct {
+		name  string
+		sorts []Sort
+		want  string
+	}{
+		{
+			name:  "default sort",
+			sorts: nil,
+			want:  `"created_at","id"`,
+		},
+		{
+			name:  "appends id as a tie breaker",
+			sorts: []Sort{{Column: "name", Descending: true}},
+			want:  `"name" DESC,"id"`,
+		},
+		{
+			name:  "keeps an explicit id",
+			sorts: []Sort{{Column: "id", Descending: true}},
+			want:  `"id" DESC`,
+		},
+	}
+
+	db := newDryRun(t)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sql := db.Unscoped().Clauses(orderBy(test.sorts)).Find(&[]testWidget{}).Statement.SQL.String()
+			want := `SELECT * FROM "test_widgets" ORDER BY ` + test.want
+
+			if sql != want {
+				t.Fatalf("expected %q, got %q", want, sql)
+			}
+		})
+	}
+}
+
+func TestIsDefaultSort(t *testing.T) {
+	tests := []struct {
+		name  string
+		sorts []Sort
+		want  bool
+	}{
+		{name: "empty", sorts: nil, want: true},
+		{name: "created at", sorts: []Sort{{Column: "created_at"}}, want: true},
+		{name: "created at and id", sorts: []Sort{{Column: "created_at"}, {Column: "id"}}, want: true},
+		{name: "descending", sorts: []Sort{{Column: "created_at", Descending: true}}, want: false},
+		{name: "other column", sorts: []Sort{{Column: "name"}}, want: false},
+		{name: "too long", sorts: []Sort{{Column: "created_at"}, {Column: "id"}, {Column: "name"}}, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isDefaultSort(test.sorts); got != test.want {
+				t.Fatalf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/connor-davis/dynamic-crud/internal/auth"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	TenantHeader     = "X-Tenant-ID"
+	TenantClaim      = "tenant_id"
+	TenantColumn     = "tenant_id"
+	TenantSystemRole = "system"
+)
+
+type tenantKey struct{}
+
+type systemKey struct{}
+
+func WithTenant(ctx context.Context, tenantId uuid.UUID) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantId)
+}
+
+func TenantFrom(ctx context.Context) (uuid.UUID, bool) {
+	tenantId, ok := ctx.Value(tenantKey{}).(uuid.UUID)
+
+	return tenantId, ok
+}
+
+func WithSystem(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemKey{}, true)
+}
+
+func IsSystem(ctx context.Context) bool {
+	system, _ := ctx.Value(systemKey{}).(bool)
+
+	return system
+}
+
+func (m *model) tenantField() *schema.Field {
+	return m.schema.FieldsByDBName[TenantColumn]
+}
+
+func (m *model) tenantOf(entity any) (uuid.UUID, bool) {
+	field := m.tenantField()
+
+	if field == nil {
+		return uuid.Nil, false
+	}
+
+	value, _ := field.ValueOf(context.Background(), reflect.ValueOf(entity).Elem())
+	tenantId, ok := value.(uuid.UUID)
+
+	return tenantId, ok
+}
+
+func (c *crud[T]) tenantRequired() error {
+	return NewError(KindForbidden, fmt.Sprintf("A tenant is required to access %ss, the credentials carry no %s claim.", strings.ToLower(c.model.schema.Name), TenantClaim))
+}
+
+func (c *crud[T]) scopeTenant(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if c.model.tenantField() == nil {
+		return db
+	}
+
+	tenantId, ok := TenantFrom(ctx)
+
+	if !ok {
+		if !IsSystem(ctx) {
+			db.AddError(c.tenantRequired())
+		}
+
+		return db
+	}
+
+	return db.Where(clause.Eq{
+		Column: clause.Column{Table: clause.CurrentTable, Name: TenantColumn},
+		Value:  tenantId,
+	})
+}
+
+func (c *crud[T]) stampTenant(ctx context.Context, entity *T) error {
+	field := c.model.tenantField()
+
+	if field == nil {
+		return nil
+	}
+
+	tenantId, ok := TenantFrom(ctx)
+
+	if !ok {
+		if IsSystem(ctx) {
+			return nil
+		}
+
+		return c.tenantRequired()
+	}
+
+	return field.Set(ctx, reflect.ValueOf(entity).Elem(), tenantId)
+}
+
+func resolveTenant(ctx *fiber.Ctx) (uuid.UUID, bool, error) {
+	claims, _ := auth.Locals(ctx)
+	header := ctx.Get(TenantHeader)
+
+	if header != "" {
+		if !tenantSystem(claims) {
+			return uuid.Nil, false, NewError(KindForbidden, fmt.Sprintf("Only %s principals can choose a tenant with the %s header.", TenantSystemRole, TenantHeader))
+		}
+
+		tenantId, err := uuid.Parse(header)
+
+		if err != nil {
+			return uuid.Nil, false, NewError(KindBadRequest, fmt.Sprintf("The %s header must be a UUID.", TenantHeader))
+		}
+
+		return tenantId, true, nil
+	}
+
+	claim := ""
+
+	if claims != nil {
+		claim = claims.String(TenantClaim)
+	}
+
+	if claim == "" {
+		return uuid.Nil, false, nil
+	}
+
+	tenantId, err := uuid.Parse(claim)
+
+	if err != nil {
+		return uuid.Nil, false, NewError(KindForbidden, fmt.Sprintf("The %s claim is not a valid tenant id.", TenantClaim))
+	}
+
+	return tenantId, true, nil
+}
+
+func tenantSystem(claims *auth.Claims) bool {
+	return claims.Grants(TenantSystemRole)
+}
+
+func (c *crudApi[T]) tenantParameters() []*openapi3.ParameterRef {
+	if c.model.tenantField() == nil {
+		return nil
+	}
+
+	return []*openapi3.ParameterRef{
+		{
+			Value: openapi3.NewHeaderParameter(TenantHeader).
+				WithDescription(fmt.Sprintf("The tenant to act on. Only principals with the %s role or scope may send it, everyone else is scoped to the tenant in their %s claim.", TenantSystemRole, TenantClaim)).
+				WithSchema(openapi3.NewUUIDSchema()),
+		},
+	}
+}
+
+func (c *crudApi[T]) tenancy() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		tenantId, ok, err := resolveTenant(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			ctx.SetUserContext(WithTenant(ctx.UserContext(), tenantId))
+		}
+
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,309 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/connor-davis/dynamic-crud/internal/auth"
+	"github.com/connor-davis/dynamic-crud/internal/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type testNote struct {
+	Id uuid.UUID `json:"id" gorm:"primaryKey;type:uuid"`
+	models.TenantBase
+	Title     string         `json:"title"`
+	DeletedAt gorm.DeletedAt `json:"deletedAt"`
+}
+
+func captureStatements(t *testing.T, db *gorm.DB) *[]string {
+	t.Helper()
+
+	statements := []string{}
+
+	capture := func(db *gorm.DB) {
+		if sql := db.Statement.SQL.String(); sql != "" {
+			statements = append(statements, db.Dialector.Explain(sql, db.Statement.Vars...))
+		}
+	}
+
+	callbacks := db.Callback()
+
+	for name, err := range map[string]error{
+		"create": callbacks.Create().Register("test:capture", capture),
+		"query":  callbacks.Query().Register("test:capture", capture),
+		"update": callbacks.Update().Register("test:capture", capture),
+		"delete": callbacks.Delete().Register("test:capture", capture),
+		"row":    callbacks.Row().Register("test:capture", capture),
+	} {
+		if err != nil {
+			t.Fatalf("failed to capture %s statements: %v", name, err)
+		}
+	}
+
+	return &statements
+}
+
+func TestCrudScopesEveryStatementToTheTenant(t *testing.T) {
+	tenant := uuid.New()
+	other := uuid.New()
+	id := uuid.New()
+
+	tests := []struct {
+		name string
+		run  func(ctx context.Context, notes Crud[testNote]) error
+	}{
+		{name: "find one", run: func(ctx context.Context, notes Crud[testNote]) error {
+			return notes.FindOne(ctx, id, nil, &testNote{})
+		}},
+		{name: "find all", run: func(ctx context.Context, notes Crud[testNote]) error {
+			return notes.FindAll(ctx, &Query{}, &[]testNote{})
+		}},
+		{name: "find by ids", run: func(ctx context.Context, notes Crud[testNote]) error {
+			return notes.FindByIds(ctx, []any{id}, &[]testNote{})
+		}},
+		{name: "find page", run: func(ctx context.Context, notes Crud[testNote]) error {
+			_, err := notes.FindPage(ctx, &Query{}, &[]testNote{})
+
+			return err
+		}},
+		{name: "find trash", run: func(ctx context.Context, notes Crud[testNote]) error {
+			_, err := notes.FindTrash(ctx, &Query{}, &[]testNote{})
+
+			return err
+		}},
+		{name: "create", run: func(ctx context.Context, notes Crud[testNote]) error {
+			return notes.Create(ctx, &testNote{Id: id, TenantBase: models.TenantBase{TenantId: other}, Title: "note"})
+		}},
+		{name: "create batch", run: func(ctx context.Context, notes Crud[testNote]) error {
+			return notes.CreateBatch(ctx, &[]testNote{{Id: id, TenantBase: models.TenantBase{TenantId: other}}}, 10)
+		}},
+		{name: "update", run: func(ctx context.Context, notes Crud[testNote]) error {
+			return notes.Update(ctx, id, &testNote{Id: id, TenantBase: models.TenantBase{TenantId: other}, Title: "note"})
+		}},
+		{name: "patch", run: func(ctx context.Context, notes Crud[testNote]) error {
+			return notes.Patch(ctx, id, &testNote{Id: id, Title: "note"}, []string{"title"})
+		}},
+		{name: "delete", run: func(ctx context.Context, notes Crud[testNote]) error {
+			return notes.Delete(ctx, id, &testNote{})
+		}},
+		{name: "hard delete", run: func(ctx context.Context, notes Crud[testNote]) error {
+			return notes.HardDelete(ctx, id, &testNote{})
+		}},
+		{name: "delete batch", run: func(ctx context.Context, notes Crud[testNote]) error {
+			_, err := notes.DeleteBatch(ctx, []any{id})
+
+			return err
+		}},
+		{name: "restore", run: func(ctx context.Context, notes Crud[testNote]) error {
+			return notes.Restore(ctx, id, &testNote{})
+		}},
+		{name: "create if absent", run: func(ctx context.Context, notes Crud[testNote]) error {
+			_, err := notes.CreateIfAbsent(ctx, &testNote{Id: id, TenantBase: models.TenantBase{TenantId: other}, Title: "note"}, []string{"id"})
+
+			return err
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := newDryRun(t)
+			statements := captureStatements(t, db)
+			notes := &crud[testNote]{model: parseModel[testNote]()}
+
+			ctx := WithTransaction(WithTenant(context.Background(), tenant), db)
+
+			if err := test.run(ctx, notes); err != nil && !IsNotFound(err) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(*statements) == 0 {
+				t.Fatal("expected at least one statement")
+			}
+
+			for _, statement := range *statements {
+				if !strings.Contains(statement, tenant.String()) {
+					t.Fatalf("statement is not scoped to the tenant: %s", statement)
+				}
+
+				if strings.Contains(statement, other.String()) {
+					t.Fatalf("statement leaks another tenant: %s", statement)
+				}
+			}
+		})
+	}
+}
+
+func TestCrudFailsClosedWithoutTenant(t *testing.T) {
+	tests := []struct {
+		name       string
+		background func(crud Crud[testNote]) BackgroundCrud[testNote]
+		wantErr    bool
+		wantScoped bool
+	}{
+		{name: "background", background: NewBackgroundCrud[testNote], wantErr: true},
+		{name: "tenant", background: func(crud Crud[testNote]) BackgroundCrud[testNote] {
+			return NewTenantCrud(crud, uuid.New())
+		}, wantScoped: true},
+		{name: "system", background: NewSystemCrud[testNote]},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := newDryRun(t)
+			statements := captureStatements(t, db)
+			notes := test.background(&crud[testNote]{storage: dryRunStorage{db}, model: parseModel[testNote]()})
+
+			findErr := notes.FindAll(&Query{}, &[]testNote{})
+			createErr := notes.Create(&testNote{Id: uuid.New()})
+
+			for _, err := range []error{findErr, createErr} {
+				if test.wantErr {
+					var crudErr *Error
+
+					if !errors.As(err, &crudErr) || crudErr.Kind != KindForbidden {
+						t.Fatalf("expected a tenant required error, got %v", err)
+					}
+				} else if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			if test.wantErr {
+				return
+			}
+
+			for _, statement := range *statements {
+				if scoped := strings.Contains(statement, TenantColumn); scoped != test.wantScoped && strings.HasPrefix(statement, "SELECT") {
+					t.Fatalf("unexpected tenant scoping in %s", statement)
+				}
+			}
+		})
+	}
+}
+
+func TestTenantRoutesForbidPrincipalsWithoutTenant(t *testing.T) {
+	tenant := uuid.New()
+
+	tests := []struct {
+		name       string
+		claims     *auth.Claims
+		header     string
+		wantStatus int
+	}{
+		{name: "anonymous", wantStatus: fiber.StatusUnauthorized},
+		{name: "no tenant claim", claims: &auth.Claims{Subject: "u1", Raw: map[string]any{}}, wantStatus: fiber.StatusForbidden},
+		{name: "tenant claim", claims: &auth.Claims{Subject: "u1", Raw: map[string]any{TenantClaim: tenant.String()}}, wantStatus: fiber.StatusOK},
+		{name: "tenant admin choosing a tenant", claims: &auth.Claims{Subject: "u2", Raw: map[string]any{"roles": []any{"admin"}}}, header: tenant.String(), wantStatus: fiber.StatusForbidden},
+		{name: "system choosing a tenant", claims: &auth.Claims{Subject: "u9", Raw: map[string]any{"roles": []any{TenantSystemRole}}}, header: tenant.String(), wantStatus: fiber.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api := newTestApi[testNote](t, nil)
+			api.AssignAuthentication(claimsAuthenticator{test.claims}, OperationGetAll)
+
+			request := httptest.NewRequest(fiber.MethodGet, "/testnotes", nil)
+
+			if test.header != "" {
+				request.Header.Set(TenantHeader, test.header)
+			}
+
+			response, err := newTestApp(api.GetAllRoute()).Test(request)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if response.StatusCode != test.wantStatus {
+				t.Fatalf("expected status %d, got %d", test.wantStatus, response.StatusCode)
+			}
+		})
+	}
+}
+
+func TestResolveTenant(t *testing.T) {
+	tenant := uuid.New()
+	other := uuid.New()
+
+	member := &auth.Claims{Subject: "u1", Raw: map[string]any{TenantClaim: tenant.String()}}
+	admin := &auth.Claims{Subject: "u2", Raw: map[string]any{TenantClaim: tenant.String(), "roles": []any{"admin"}}}
+	adminKey := &auth.Claims{Subject: "apikey:1", Raw: map[string]any{TenantClaim: tenant.String(), "scopes": []any{"admin"}}}
+	system := &auth.Claims{Subject: "u9", Raw: map[string]any{"roles": []any{TenantSystemRole}}}
+	systemKey := &auth.Claims{Subject: "apikey:2", Raw: map[string]any{"scopes": []any{TenantSystemRole}}}
+
+	tests := []struct {
+		name     string
+		claims   *auth.Claims
+		header   string
+		want     uuid.UUID
+		wantOk   bool
+		wantKind Kind
+	}{
+		{name: "anonymous"},
+		{name: "tenant claim", claims: member, want: tenant, wantOk: true},
+		{name: "claim without tenant", claims: &auth.Claims{Subject: "u3", Raw: map[string]any{}}},
+		{name: "invalid tenant claim", claims: &auth.Claims{Subject: "u3", Raw: map[string]any{TenantClaim: "nope"}}, wantKind: KindForbidden},
+		{name: "header from anonymous caller", header: other.String(), wantKind: KindForbidden},
+		{name: "header from member", claims: member, header: other.String(), wantKind: KindForbidden},
+		{name: "matching header from member", claims: member, header: tenant.String(), wantKind: KindForbidden},
+		{name: "admin without header", claims: admin, want: tenant, wantOk: true},
+		{name: "header from tenant admin role", claims: admin, header: other.String(), wantKind: KindForbidden},
+		{name: "header from tenant admin scope", claims: adminKey, header: other.String(), wantKind: KindForbidden},
+		{name: "header from system role", claims: system, header: other.String(), want: other, wantOk: true},
+		{name: "header from system scope", claims: systemKey, header: other.String(), want: other, wantOk: true},
+		{name: "invalid header from system", claims: system, header: "nope", wantKind: KindBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := newRequestCtx(t, "/notes")
+
+			if test.claims != nil {
+				auth.SetClaims(ctx, test.claims)
+			}
+
+			if test.header != "" {
+				ctx.Request().Header.Set(TenantHeader, test.header)
+			}
+
+			tenantId, ok, err := resolveTenant(ctx)
+
+			if test.wantKind != "" {
+				var crudErr *Error
+
+				if !errors.As(err, &crudErr) || crudErr.Kind != test.wantKind {
+					t.Fatalf("expected a %s error, got %v", test.wantKind, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if ok != test.wantOk || tenantId != test.want {
+				t.Fatalf("expected %v %v, got %v %v", test.want, test.wantOk, tenantId, ok)
+			}
+		})
+	}
+}
+
+func TestTenantParameters(t *testing.T) {
+	notes := &crudApi[testNote]{model: parseModel[testNote]()}
+	widgets := &crudApi[testWidget]{model: parseModel[testWidget]()}
+
+	if parameters := notes.tenantParameters(); len(parameters) != 1 || parameters[0].Value.Name != TenantHeader || parameters[0].Value.In != "header" {
+		t.Fatalf("expected the %s header parameter, got %#v", TenantHeader, parameters)
+	}
+
+	if parameters := widgets.tenantParameters(); len(parameters) != 0 {
+		t.Fatalf("expected no tenant parameters, got %#v", parameters)
+	}
+}
@@ -0,0 +1,98 @@
+package crud
+
+import (
+	"context"
+	"sync"
+
+	"github.com/connor-davis/dynamic-crud/internal/storage"
+	"gorm.io/gorm"
+)
+
+type transactionKey struct{}
+
+type commitHooksKey struct{}
+
+type commitHooks struct {
+	mutex     sync.Mutex
+	callbacks []func()
+}
+
+func WithTransaction(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, transactionKey{}, tx)
+}
+
+func TransactionFrom(ctx context.Context) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(transactionKey{}).(*gorm.DB)
+
+	return tx, ok
+}
+
+func withCommitHooks(ctx context.Context) (context.Context, *commitHooks) {
+	hooks := &commitHooks{}
+
+	return context.WithValue(ctx, commitHooksKey{}, hooks), hooks
+}
+
+func AfterCommit(ctx context.Context, callback func()) {
+	hooks, ok := ctx.Value(commitHooksKey{}).(*commitHooks)
+
+	if _, inTransaction := TransactionFrom(ctx); !ok || !inTransaction {
+		callback()
+
+		return
+	}
+
+	hooks.mutex.Lock()
+	defer hooks.mutex.Unlock()
+
+	hooks.callbacks = append(hooks.callbacks, callback)
+}
+
+func (h *commitHooks) run() {
+	h.mutex.Lock()
+	callbacks := h.callbacks
+	h.callbacks = nil
+	h.mutex.Unlock()
+
+	for _, callback := range callbacks {
+		callback()
+	}
+}
+
+func (c *crud[T]) database(ctx context.Context) *gorm.DB {
+	if tx, ok := TransactionFrom(ctx); ok {
+		return c.scopeTenant(ctx, tx.WithContext(ctx))
+	}
+
+	return c.scopeTenant(ctx, c.storage.Database().WithContext(ctx))
+}
+
+func transaction(ctx context.Context, storage storage.Storage, fn func(ctx context.Context) error) error {
+	if _, ok := TransactionFrom(ctx); ok {
+		return fn(ctx)
+	}
+
+	ctx, hooks := withCommitHooks(ctx)
+
+	if err := storage.Database().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(WithTransaction(ctx, tx))
+	}); err != nil {
+		return err
+	}
+
+	hooks.run()
+
+	return nil
+}
+
+func savepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, ok := TransactionFrom(ctx)
+
+	if !ok {
+		return fn(ctx)
+	}
+
+	return tx.WithContext(ctx).Transaction(func(nested *gorm.DB) error {
+		return fn(WithTransaction(ctx, nested))
+	})
+}
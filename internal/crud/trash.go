@@ -0,0 +1,39 @@
+package crud
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func (c *crudApi[T]) deleteDescription() string {
+	if !c.model.softDeletable() {
+		return fmt.Sprintf("This endpoint deletes an existing %s.", strings.ToLower(c.name))
+	}
+
+	return fmt.Sprintf(
+		"This endpoint moves an existing %s to the trash. Pass hard=true to delete it permanently, which is restricted to administrators.",
+		strings.ToLower(c.name),
+	)
+}
+
+func (c *crudApi[T]) deleteParameters() []*openapi3.ParameterRef {
+	parameters := []*openapi3.ParameterRef{
+		{
+			Value: openapi3.NewPathParameter("id").
+				WithRequired(true).
+				WithSchema(openapi3.NewUUIDSchema()),
+		},
+	}
+
+	if c.model.softDeletable() {
+		parameters = append(parameters, &openapi3.ParameterRef{
+			Value: openapi3.NewQueryParameter("hard").
+				WithDescription("Permanently delete the entity instead of moving it to the trash.").
+				WithSchema(openapi3.NewBoolSchema().WithDefault(false)),
+		})
+	}
+
+	return append(parameters, c.tenantParameters()...)
+}
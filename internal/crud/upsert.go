@@ -0,0 +1,362 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/connor-davis/dynamic-crud/internal/routing"
+	"github.com/connor-davis/dynamic-crud/internal/routing/schemas"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm/clause"
+)
+
+func (c *crud[T]) CreateIfAbsent(ctx context.Context, entity *T, columns []string) (bool, error) {
+	if err := c.stampTenant(ctx, entity); err != nil {
+		return false, err
+	}
+
+	conflict := []clause.Column{}
+
+	for _, column := range columns {
+		conflict = append(conflict, clause.Column{Name: column})
+	}
+
+	result := c.database(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   conflict,
+			DoNothing: true,
+		}).
+		Create(entity)
+
+	if result.Error != nil {
+		return false, c.translate(result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func trashedConflict(name string, fields []string) error {
+	return NewError(KindConflict, fmt.Sprintf("A deleted %s with the same %s exists, restore it before upserting.", strings.ToLower(name), strings.Join(fields, ", ")))
+}
+
+func (c *crudApi[T]) findUpsertMatch(ctx context.Context, entity *T, columns []string) (*T, error) {
+	query := &Query{
+		Unscoped: true,
+		Lock:     true,
+	}
+
+	for _, column := range columns {
+		value, _ := c.model.schema.FieldsByDBName[column].ValueOf(ctx, reflect.ValueOf(entity).Elem())
+
+		query.Scopes = append(query.Scopes, clause.Eq{
+			Column: clause.Column{Table: clause.CurrentTable, Name: column},
+			Value:  value,
+		})
+	}
+
+	query, err := c.scope(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []T{}
+
+	if err := c.crud.FindAll(ctx, query, &matches); err != nil {
+		return nil, err
+	}
+
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	if c.model.trashed(&matches[0]) {
+		return nil, trashedConflict(c.name, c.model.jsonNames(columns))
+	}
+
+	return &matches[0], nil
+}
+
+func (c *crudApi[T]) upsertInsert(ctx context.Context, ifMatch string, entity *T, columns []string) (bool, error) {
+	if ifMatch != "" {
+		return false, NewError(KindPreconditionFailed, fmt.Sprintf("The %s does not exist yet.", strings.ToLower(c.name)))
+	}
+
+	if err := c.authorize(ctx, OperationCreate, entity); err != nil {
+		return false, err
+	}
+
+	if err := c.guardWrites(ctx, nil, entity); err != nil {
+		return false, err
+	}
+
+	if err := runHooks(ctx, c.hooks.beforeCreate, entity); err != nil {
+		return false, err
+	}
+
+	inserted, err := c.crud.CreateIfAbsent(ctx, entity, columns)
+
+	if err != nil || !inserted {
+		return false, err
+	}
+
+	return true, runHooks(ctx, c.hooks.afterCreate, entity)
+}
+
+func (c *crudApi[T]) upsertUpdate(ctx context.Context, ifMatch string, existing *T, entity *T) error {
+	versions, err := c.expectedVersions(ifMatch, "The If-Match header")
+
+	if err != nil {
+		return err
+	}
+
+	if err := c.checkVersion(existing, versions); err != nil {
+		return err
+	}
+
+	if err := c.authorize(ctx, OperationUpdate, existing); err != nil {
+		return err
+	}
+
+	if err := c.authorize(ctx, OperationUpsert, existing); err != nil {
+		return err
+	}
+
+	id := c.model.primaryKey(existing)
+
+	if err := c.model.setPrimaryKey(entity, id); err != nil {
+		return err
+	}
+
+	if err := c.guardWrites(ctx, existing, entity); err != nil {
+		return err
+	}
+
+	if err := runHooks(ctx, c.hooks.beforeUpdate, entity); err != nil {
+		return err
+	}
+
+	if err := c.crud.Update(withVersions(ctx, versions), id, entity); err != nil {
+		return err
+	}
+
+	return runHooks(ctx, c.hooks.afterUpdate, entity)
+}
+
+func (c *crudApi[T]) upsert(ctx context.Context, ifMatch string, entity *T, columns []string) (bool, error) {
+	existing, err := c.findUpsertMatch(ctx, entity, columns)
+
+	if err != nil {
+		return false, err
+	}
+
+	if existing == nil {
+		candidate := *entity
+
+		inserted, err := c.upsertInsert(ctx, ifMatch, &candidate, columns)
+
+		if err != nil || inserted {
+			*entity = candidate
+
+			return inserted, err
+		}
+
+		if existing, err = c.findUpsertMatch(ctx, entity, columns); err != nil {
+			return false, err
+		}
+
+		if existing == nil {
+			return false, NewError(KindConflict, fmt.Sprintf("A %s with the same %s already exists.", strings.ToLower(c.name), strings.Join(c.model.jsonNames(columns), ", ")))
+		}
+	}
+
+	return false, c.upsertUpdate(ctx, ifMatch, existing, entity)
+}
+
+func (c *crudApi[T]) parseUpsertKey(ctx *fiber.Ctx) ([]string, error) {
+	names := []string{}
+
+	for _, name := range strings.Split(ctx.Query("on"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	for _, key := range c.model.uniqueKeys() {
+		if !slices.Equal(slices.Sorted(slices.Values(key)), names) {
+			continue
+		}
+
+		columns := []string{}
+
+		for _, name := range key {
+			field, _ := c.model.field(name)
+			columns = append(columns, field.Column)
+		}
+
+		return columns, nil
+	}
+
+	return nil, fmt.Errorf("on must be one of the unique keys %s", strings.Join(c.upsertKeys(), ", "))
+}
+
+func (c *crudApi[T]) upsertKeys() []string {
+	keys := []string{}
+
+	for _, key := range c.model.uniqueKeys() {
+		keys = append(keys, strings.Join(key, ","))
+	}
+
+	return keys
+}
+
+func (c *crudApi[T]) upsertKeyParameter() *openapi3.ParameterRef {
+	keys := []any{}
+
+	for _, key := range c.upsertKeys() {
+		keys = append(keys, key)
+	}
+
+	return &openapi3.ParameterRef{
+		Value: openapi3.NewQueryParameter("on").
+			WithRequired(true).
+			WithDescription(fmt.Sprintf("The unique key used to find an existing %s, multiple fields are separated by a comma.", strings.ToLower(c.name))).
+			WithSchema(openapi3.NewStringSchema().WithEnum(keys...)),
+	}
+}
+
+func (c *crudApi[T]) UpsertRoute() routing.Route {
+	if len(c.model.uniqueKeys()) == 0 {
+		panic(fmt.Sprintf("cannot upsert %s because it has no unique fields", c.name))
+	}
+
+	responses := openapi3.NewResponses()
+
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription(fmt.Sprintf("An existing %s was updated.", strings.ToLower(c.name))).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchemaRef(c.upsertRef()),
+			}),
+	})
+
+	responses.Set("201", &openapi3.ResponseRef{
+		Value: &openapi3.Response{
+			Description: openapi3.Ptr(fmt.Sprintf("A new %s was inserted.", strings.ToLower(c.name))),
+			Headers: openapi3.Headers{
+				"Location": locationHeader(),
+			},
+			Content: openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchemaRef(c.upsertRef()),
+			},
+		},
+	})
+
+	responses.Set("400", schemas.NewErrorResponse("Bad Request"))
+	responses.Set("401", schemas.NewErrorResponse("Unauthorized"))
+	responses.Set("403", schemas.NewErrorResponse("Forbidden"))
+	responses.Set("409", schemas.NewErrorResponse("Conflict"))
+	responses.Set("412", schemas.NewErrorResponse("Precondition Failed"))
+	responses.Set("422", schemas.NewValidationErrorResponse("Unprocessable Entity"))
+	responses.Set("428", schemas.NewErrorResponse("Precondition Required"))
+	responses.Set("500", schemas.NewErrorResponse("Internal Server Error"))
+	responses.Set("503", schemas.NewErrorResponse("Service Unavailable"))
+	responses.Set("504", schemas.NewErrorResponse("Gateway Timeout"))
+
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     fmt.Sprintf("Upsert %s", c.name),
+			Description: fmt.Sprintf("This endpoint creates a %s, or updates the existing %s with the same unique key. A deleted %s with the same key must be restored first.", strings.ToLower(c.name), strings.ToLower(c.name), strings.ToLower(c.name)),
+			Tags:        []string{fmt.Sprintf("%ss", c.name)},
+			Parameters: append([]*openapi3.ParameterRef{
+				c.upsertKeyParameter(),
+				ifMatchParameter(),
+			}, c.tenantParameters()...),
+			RequestBody: &openapi3.RequestBodyRef{
+				Value: openapi3.NewRequestBody().
+					WithRequired(true).
+					WithJSONSchemaRef(c.createRef()).
+					WithDescription(fmt.Sprintf("Payload to create or update a %s.", strings.ToLower(c.name))),
+			},
+			Responses: responses,
+			Security:  c.security(OperationUpsert),
+		},
+		Entity:          c.name,
+		Schemas:         c.schemas(),
+		SecuritySchemes: c.securitySchemes(),
+		Method:          routing.POST,
+		Path:            fmt.Sprintf("/%ss/upsert", strings.ToLower(c.name)),
+		Middlewares:     c.middlewares(OperationUpsert),
+		Handler: func(ctx *fiber.Ctx) error {
+			queryCtx, cancel := c.queryContext(ctx, OperationUpsert)
+			defer cancel()
+
+			columns, err := c.parseUpsertKey(ctx)
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			var entity T
+
+			fieldErrors, err := c.decodeBody(ctx.Body(), c.create, &entity)
+
+			if err != nil {
+				return NewError(KindBadRequest, err.Error())
+			}
+
+			if len(fieldErrors) > 0 {
+				return NewValidationError(fmt.Sprintf("The %s payload is invalid.", strings.ToLower(c.name)), fieldErrors)
+			}
+
+			ifMatch := strings.TrimSpace(ctx.Get(fiber.HeaderIfMatch))
+			inserted := false
+
+			if err := transaction(queryCtx, c.storage, func(txCtx context.Context) error {
+				inserted, err = c.upsert(txCtx, ifMatch, &entity, columns)
+
+				return err
+			}); err != nil {
+				return err
+			}
+
+			id := c.model.primaryKey(&entity)
+
+			if err := c.crud.FindOne(queryCtx, id, nil, &entity); err != nil {
+				return err
+			}
+
+			if err := runHooks(queryCtx, c.hooks.afterFind, &entity); err != nil {
+				return err
+			}
+
+			item, _, err := c.present(queryCtx, &entity, nil)
+
+			if err != nil {
+				return err
+			}
+
+			if !inserted {
+				return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+					"item":      item,
+					"operation": "updated",
+				})
+			}
+
+			ctx.Location(fmt.Sprintf("%s/%v", strings.TrimSuffix(strings.TrimSuffix(ctx.Path(), "/"), "/upsert"), id))
+
+			return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{
+				"item":      item,
+				"operation": "inserted",
+			})
+		},
+	}
+}
@@ -0,0 +1,130 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/connor-davis/dynamic-crud/internal/auth"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type upsertCrud struct {
+	Crud[testRevision]
+	matches []testRevision
+	raced   []testRevision
+	created []testRevision
+	updated []testRevision
+}
+
+func (u *upsertCrud) FindAll(ctx context.Context, query *Query, entities *[]testRevision) error {
+	if !query.Unscoped || !query.Lock {
+		return errors.New("the upsert match must be locked and include deleted rows")
+	}
+
+	*entities = append(*entities, u.matches...)
+
+	return nil
+}
+
+func (u *upsertCrud) CreateIfAbsent(ctx context.Context, entity *testRevision, columns []string) (bool, error) {
+	if len(u.raced) > 0 {
+		u.matches = append(u.matches, u.raced...)
+
+		return false, nil
+	}
+
+	u.created = append(u.created, *entity)
+
+	return true, nil
+}
+
+func (u *upsertCrud) Update(ctx context.Context, entityId any, entity *testRevision) error {
+	if entity.Id != entityId {
+		return errors.New("the update must target the matched row")
+	}
+
+	u.updated = append(u.updated, *entity)
+
+	return nil
+}
+
+func TestUpsertRouting(t *testing.T) {
+	existing := testRevision{Id: uuid.New(), Slug: "intro", Title: "Old", Version: 2}
+	trashed := existing
+	trashed.DeletedAt = gorm.DeletedAt{Valid: true}
+
+	denyUpdates := func(ctx context.Context, claims *auth.Claims, entity *testRevision) error {
+		return errors.New("updates are not allowed")
+	}
+
+	tests := []struct {
+		name         string
+		matches      []testRevision
+		raced        []testRevision
+		ifMatch      string
+		updatePolicy Policy[testRevision]
+		upsertPolicy Policy[testRevision]
+		wantKind     Kind
+		wantInserted bool
+		wantCreated  int
+		wantUpdated  int
+	}{
+		{name: "inserts when nothing matches", wantInserted: true, wantCreated: 1},
+		{name: "updates the matching row", matches: []testRevision{existing}, wantUpdated: 1},
+		{name: "updates with a current etag", matches: []testRevision{existing}, ifMatch: `"2"`, wantUpdated: 1},
+		{name: "rejects a stale etag", matches: []testRevision{existing}, ifMatch: `"1"`, wantKind: KindPreconditionFailed},
+		{name: "rejects an etag when inserting", ifMatch: `"1"`, wantKind: KindPreconditionFailed},
+		{name: "applies update policies to the matching row", matches: []testRevision{existing}, updatePolicy: denyUpdates, wantKind: KindForbidden},
+		{name: "applies upsert policies to the matching row", matches: []testRevision{existing}, upsertPolicy: denyUpdates, wantKind: KindForbidden},
+		{name: "inserts under the create policies only", upsertPolicy: denyUpdates, wantInserted: true, wantCreated: 1},
+		{name: "refuses to restore a deleted row", matches: []testRevision{trashed}, wantKind: KindConflict},
+		{name: "updates a row inserted concurrently", raced: []testRevision{existing}, wantUpdated: 1},
+		{name: "applies update policies to a row inserted concurrently", raced: []testRevision{existing}, updatePolicy: denyUpdates, wantKind: KindForbidden},
+		{name: "refuses a deleted row inserted concurrently", raced: []testRevision{trashed}, wantKind: KindConflict},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := &upsertCrud{matches: test.matches, raced: test.raced}
+			api := &crudApi[testRevision]{
+				name:     "Revision",
+				model:    parseModel[testRevision](),
+				crud:     fake,
+				policies: map[Operation][]Policy[testRevision]{},
+			}
+
+			if test.updatePolicy != nil {
+				api.policies[OperationUpdate] = append(api.policies[OperationUpdate], test.updatePolicy)
+			}
+
+			if test.upsertPolicy != nil {
+				api.policies[OperationUpsert] = append(api.policies[OperationUpsert], test.upsertPolicy)
+			}
+
+			entity := &testRevision{Slug: "intro", Title: "New"}
+			ctx := context.Background()
+
+			inserted, err := api.upsert(ctx, test.ifMatch, entity, []string{"slug"})
+
+			if test.wantKind != "" {
+				var crudError *Error
+
+				if !errors.As(err, &crudError) || crudError.Kind != test.wantKind {
+					t.Fatalf("expected a %s error, got %v", test.wantKind, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if inserted != test.wantInserted {
+				t.Fatalf("expected inserted to be %v, got %v", test.wantInserted, inserted)
+			}
+
+			if len(fake.created) != test.wantCreated || len(fake.updated) != test.wantUpdated {
+				t.Fatalf("expected %d creates and %d updates, got %d and %d", test.wantCreated, test.wantUpdated, len(fake.created), len(fake.updated))
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package crud
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/connor-davis/dynamic-crud/internal/routing/schemas"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-playground/validator/v10"
+)
+
+type FieldError struct {
+	Path    string `json:"path"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type Validatable interface {
+	Validate() error
+}
+
+var validate = validator.New()
+
+func validateSchema(schema *openapi3.Schema, payload map[string]any) []FieldError {
+	if schema == nil {
+		return nil
+	}
+
+	err := schema.VisitJSON(payload, openapi3.MultiErrors(), openapi3.VisitAsRequest())
+
+	if err == nil {
+		return nil
+	}
+
+	fieldErrors := []FieldError{}
+
+	var multiError openapi3.MultiError
+
+	if !errors.As(err, &multiError) {
+		multiError = openapi3.MultiError{err}
+	}
+
+	for _, err := range flattenMultiError(multiError) {
+		var schemaError *openapi3.SchemaError
+
+		if !errors.As(err, &schemaError) {
+			fieldErrors = append(fieldErrors, FieldError{
+				Path:    "/",
+				Rule:    "schema",
+				Message: err.Error(),
+			})
+
+			continue
+		}
+
+		fieldErrors = append(fieldErrors, FieldError{
+			Path:    "/" + strings.Join(schemaError.JSONPointer(), "/"),
+			Rule:    schemaError.SchemaField,
+			Message: schemaError.Reason,
+		})
+	}
+
+	return fieldErrors
+}
+
+func flattenMultiError(multiError openapi3.MultiError) []error {
+	flattened := []error{}
+
+	for _, err := range multiError {
+		var nested openapi3.MultiError
+
+		if errors.As(err, &nested) {
+			flattened = append(flattened, flattenMultiError(nested)...)
+
+			continue
+		}
+
+		flattened = append(flattened, err)
+	}
+
+	return flattened
+}
+
+func validateEntity[T any](entity *T) []FieldError {
+	var err error
+
+	if validatable, ok := any(entity).(Validatable); ok {
+		err = validatable.Validate()
+	} else {
+		err = validate.Struct(entity)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+
+	if !errors.As(err, &validationErrors) {
+		return []FieldError{
+			{
+				Path:    "/",
+				Rule:    "validate",
+				Message: err.Error(),
+			},
+		}
+	}
+
+	fieldErrors := []FieldError{}
+
+	for _, validationError := range validationErrors {
+		fieldErrors = append(fieldErrors, FieldError{
+			Path:    jsonPath(reflect.TypeOf(entity).Elem(), validationError.StructNamespace()),
+			Rule:    validationError.Tag(),
+			Message: validationMessage(validationError),
+		})
+	}
+
+	return fieldErrors
+}
+
+func jsonPath(modelType reflect.Type, namespace string) string {
+	segments := strings.Split(namespace, ".")[1:]
+	path := []string{}
+
+	for _, segment := range segments {
+		name, index, _ := strings.Cut(segment, "[")
+
+		for modelType.Kind() == reflect.Pointer || modelType.Kind() == reflect.Slice || modelType.Kind() == reflect.Array {
+			modelType = modelType.Elem()
+		}
+
+		field, exists := modelType.FieldByName(name)
+
+		if !exists || modelType.Kind() != reflect.Struct {
+			path = append(path, name)
+
+			continue
+		}
+
+		modelType = field.Type
+
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			continue
+		}
+
+		jsonName, _ := schemas.JsonName(field)
+
+		path = append(path, jsonName)
+
+		if index != "" {
+			path = append(path, strings.TrimSuffix(index, "]"))
+		}
+	}
+
+	return "/" + strings.Join(path, "/")
+}
+
+func validationMessage(fieldError validator.FieldError) string {
+	isText := fieldError.Kind() == reflect.String
+
+	switch fieldError.Tag() {
+	case "required":
+		return "This field is required."
+	case "email":
+		return "This field must be a valid email address."
+	case "url", "uri":
+		return "This field must be a valid URL."
+	case "uuid", "uuid4":
+		return "This field must be a valid UUID."
+	case "oneof":
+		return fmt.Sprintf("This field must be one of %s.", strings.Join(strings.Fields(fieldError.Param()), ", "))
+	case "min", "gte":
+		if isText {
+			return fmt.Sprintf("This field must be at least %s characters long.", fieldError.Param())
+		}
+
+		return fmt.Sprintf("This field must be at least %s.", fieldError.Param())
+	case "max", "lte":
+		if isText {
+			return fmt.Sprintf("This field must be at most %s characters long.", fieldError.Param())
+		}
+
+		return fmt.Sprintf("This field must be at most %s.", fieldError.Param())
+	case "gt":
+		return fmt.Sprintf("This field must be greater than %s.", fieldError.Param())
+	case "lt":
+		return fmt.Sprintf("This field must be less than %s.", fieldError.Param())
+	case "len":
+		return fmt.Sprintf("This field must have a length of %s.", fieldError.Param())
+	}
+
+	return fmt.Sprintf("This field failed the %s rule.", fieldError.Tag())
+}
+
+func (c *crudApi[T]) decodeBody(body []byte, schema *openapi3.Schema, entity *T) ([]FieldError, error) {
+	payload := map[string]any{}
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("the request body must be a JSON object: %w", err)
+	}
+
+	if err := c.guardWritableFields(schema, payload); err != nil {
+		return nil, err
+	}
+
+	if fieldErrors := validateSchema(schema, payload); len(fieldErrors) > 0 {
+		return fieldErrors, nil
+	}
+
+	body, err := json.Marshal(payload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(body, entity); err != nil {
+		return nil, fmt.Errorf("the request body does not match the %s schema: %w", strings.ToLower(c.name), err)
+	}
+
+	return validateEntity(entity), nil
+}
@@ -0,0 +1,359 @@
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/connor-davis/dynamic-crud/internal/auth"
+	"github.com/connor-davis/dynamic-crud/internal/routing/schemas"
+	"github.com/getkin/kin-openapi/openapi3"
+	"gorm.io/gorm/schema"
+)
+
+const OwnerRole = "owner"
+
+type Owner[T any] func(claims *auth.Claims, entity *T) bool
+
+var (
+	readRolesCache sync.Map
+	accessSentence = regexp.MustCompile(`\s*(Visible only to|Writable only by) [^.]*\.`)
+)
+
+func (c *crudApi[T]) AssignOwner(owner Owner[T]) CrudApi[T] {
+	c.owner = owner
+
+	return c
+}
+
+func (c *crudApi[T]) AssignReadRoles(field string, roles ...string) CrudApi[T] {
+	if _, exists := c.entity.Properties[field]; !exists {
+		panic(fmt.Sprintf("cannot restrict reading %s by unknown field %q", c.name, field))
+	}
+
+	if len(roles) == 0 {
+		delete(c.readRoles, field)
+	} else {
+		c.readRoles[field] = roles
+	}
+
+	return c
+}
+
+func (c *crudApi[T]) AssignWriteRoles(field string, roles ...string) CrudApi[T] {
+	if _, exists := c.entity.Properties[field]; !exists {
+		panic(fmt.Sprintf("cannot restrict writing %s by unknown field %q", c.name, field))
+	}
+
+	if len(roles) == 0 {
+		delete(c.writeRoles, field)
+	} else {
+		c.writeRoles[field] = roles
+	}
+
+	return c
+}
+
+func tagRoles(parsed *schema.Schema, roles func(field reflect.StructField) []string) map[string][]string {
+	rules := map[string][]string{}
+
+	for _, field := range parsed.Fields {
+		name := jsonName(field)
+
+		if name == "" {
+			continue
+		}
+
+		if fieldRoles := roles(field.StructField); len(fieldRoles) > 0 {
+			rules[name] = fieldRoles
+		}
+	}
+
+	return rules
+}
+
+func readRoles(parsed *schema.Schema) map[string][]string {
+	if rules, exists := readRolesCache.Load(parsed); exists {
+		return rules.(map[string][]string)
+	}
+
+	rules := tagRoles(parsed, schemas.ReadRoles)
+	readRolesCache.Store(parsed, rules)
+
+	return rules
+}
+
+func hasRelationRoles(parsed *schema.Schema, visited map[*schema.Schema]bool) bool {
+	for _, relationship := range parsed.Relationships.Relations {
+		if visited[relationship.FieldSchema] {
+			continue
+		}
+
+		visited[relationship.FieldSchema] = true
+
+		if len(readRoles(relationship.FieldSchema)) > 0 || hasRelationRoles(relationship.FieldSchema, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func permitted(claims *auth.Claims, roles []string, owns func() bool) bool {
+	if len(roles) == 0 {
+		return true
+	}
+
+	for _, role := range roles {
+		if role == OwnerRole {
+			if owns != nil && owns() {
+				return true
+			}
+
+			continue
+		}
+
+		if claims.Grants(role) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *crudApi[T]) owns(claims *auth.Claims, entity *T) func() bool {
+	return func() bool {
+		return c.owner != nil && claims != nil && c.owner(claims, entity)
+	}
+}
+
+func (c *crudApi[T]) hiddenFields(ctx context.Context, entity *T) []string {
+	claims, _ := auth.ClaimsFrom(ctx)
+	hidden := []string{}
+
+	var owns func() bool
+
+	if entity != nil {
+		owns = c.owns(claims, entity)
+	}
+
+	for field, roles := range c.readRoles {
+		if !permitted(claims, roles, owns) {
+			hidden = append(hidden, field)
+		}
+	}
+
+	return hidden
+}
+
+func (c *crudApi[T]) guardFilters(ctx context.Context, filters []Filter) error {
+	hidden := c.hiddenFields(ctx, nil)
+
+	for _, filter := range filters {
+		for _, name := range c.model.jsonNames([]string{filter.Column}) {
+			if slices.Contains(hidden, name) {
+				return fmt.Errorf("filtering by %q is not allowed", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *crudApi[T]) readableSortFields(ctx context.Context) []string {
+	hidden := c.hiddenFields(ctx, nil)
+
+	return slices.DeleteFunc(slices.Clone(c.sortable), func(name string) bool {
+		return slices.Contains(hidden, name)
+	})
+}
+
+func (c *crudApi[T]) present(ctx context.Context, entity *T, fields []string) (any, bool, error) {
+	item, err := project(entity, fields)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(c.readRoles) == 0 && !c.relationRoles {
+		return item, false, nil
+	}
+
+	values, ok := item.(map[string]any)
+
+	if !ok {
+		if values, err = toValues(item); err != nil {
+			return nil, false, err
+		}
+	}
+
+	redacted := false
+
+	for _, field := range c.hiddenFields(ctx, entity) {
+		if _, exists := values[field]; exists {
+			delete(values, field)
+			redacted = true
+		}
+	}
+
+	if c.relationRoles {
+		claims, _ := auth.ClaimsFrom(ctx)
+
+		redacted = redactRelations(values, c.model.schema, claims) || redacted
+	}
+
+	return values, redacted, nil
+}
+
+func (c *crudApi[T]) presentAll(ctx context.Context, entities []T, fields []string) ([]any, error) {
+	items := []any{}
+
+	for index := range entities {
+		item, _, err := c.present(ctx, &entities[index], fields)
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func toValues(item any) (map[string]any, error) {
+	data, err := json.Marshal(item)
+
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]any{}
+
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func redactRelations(values map[string]any, parsed *schema.Schema, claims *auth.Claims) bool {
+	redacted := false
+
+	for _, relationship := range parsed.Relationships.Relations {
+		redacted = redactRelation(values[jsonName(relationship.Field)], relationship.FieldSchema, claims) || redacted
+	}
+
+	return redacted
+}
+
+func redactRelation(value any, parsed *schema.Schema, claims *auth.Claims) bool {
+	redacted := false
+
+	switch value := value.(type) {
+	case map[string]any:
+		for field, roles := range readRoles(parsed) {
+			if _, exists := value[field]; exists && !permitted(claims, roles, nil) {
+				delete(value, field)
+				redacted = true
+			}
+		}
+
+		redacted = redactRelations(value, parsed, claims) || redacted
+	case []any:
+		for _, item := range value {
+			redacted = redactRelation(item, parsed, claims) || redacted
+		}
+	}
+
+	return redacted
+}
+
+func (c *crudApi[T]) guardWrites(ctx context.Context, current *T, entity *T) error {
+	if len(c.writeRoles) == 0 {
+		return nil
+	}
+
+	owner := current
+
+	if current == nil {
+		owner = entity
+		current = new(T)
+	}
+
+	beforeValues, err := toValues(current)
+
+	if err != nil {
+		return err
+	}
+
+	afterValues, err := toValues(entity)
+
+	if err != nil {
+		return err
+	}
+
+	claims, _ := auth.ClaimsFrom(ctx)
+	rejected := []string{}
+
+	for field, roles := range c.writeRoles {
+		if reflect.DeepEqual(beforeValues[field], afterValues[field]) {
+			continue
+		}
+
+		if !permitted(claims, roles, c.owns(claims, owner)) {
+			rejected = append(rejected, field)
+		}
+	}
+
+	if len(rejected) == 0 {
+		return nil
+	}
+
+	slices.Sort(rejected)
+
+	return NewError(KindForbidden, fmt.Sprintf("You are not allowed to change the following fields: %s.", strings.Join(rejected, ", ")))
+}
+
+func (c *crudApi[T]) describeAccess() {
+	for _, target := range []*openapi3.Schema{c.entity, c.create, c.update} {
+		if target == nil {
+			continue
+		}
+
+		for name, property := range target.Properties {
+			if property == nil || property.Value == nil {
+				continue
+			}
+
+			sentences := []string{}
+
+			if description := strings.TrimSpace(accessSentence.ReplaceAllString(property.Value.Description, "")); description != "" {
+				sentences = append(sentences, description)
+			}
+
+			if roles := c.readRoles[name]; len(roles) > 0 && target == c.entity {
+				sentences = append(sentences, fmt.Sprintf("Visible only to %s.", describeRoles(roles)))
+			}
+
+			if roles := c.writeRoles[name]; len(roles) > 0 {
+				sentences = append(sentences, fmt.Sprintf("Writable only by %s.", describeRoles(roles)))
+			}
+
+			property.Value.Description = strings.Join(sentences, " ")
+		}
+	}
+}
+
+func describeRoles(roles []string) string {
+	if len(roles) == 1 {
+		return roles[0]
+	}
+
+	return fmt.Sprintf("%s or %s", strings.Join(roles[:len(roles)-1], ", "), roles[len(roles)-1])
+}
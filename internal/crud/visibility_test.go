@@ -0,0 +1,109 @@
+package crud
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/connor-davis/dynamic-crud/internal/auth"
+)
+
+func TestListQueryRejectsUnreadableFields(t *testing.T) {
+	admin := &auth.Claims{Subject: "u2", Raw: map[string]any{"roles": []any{"admin"}}}
+	member := &auth.Claims{Subject: "u1", Raw: map[string]any{}}
+	adminKey := &auth.Claims{Subject: "apikey:1", Raw: map[string]any{"scopes": []any{"admin"}}}
+
+	tests := []struct {
+		name    string
+		claims  *auth.Claims
+		query   string
+		wantErr string
+	}{
+		{name: "filter on readable field", claims: member, query: "filter[count][gte]=3"},
+		{name: "filter on hidden field", claims: member, query: "filter[price][gt]=10", wantErr: `filtering by "price" is not allowed`},
+		{name: "filter on hidden field anonymously", query: "filter[price]=10", wantErr: `filtering by "price" is not allowed`},
+		{name: "filter on hidden field with role", claims: admin, query: "filter[price][gt]=10"},
+		{name: "filter on hidden field with scope", claims: adminKey, query: "filter[price][gt]=10"},
+		{name: "sort on readable field", claims: member, query: "sort=-count"},
+		{name: "sort on hidden field", claims: member, query: "sort=price", wantErr: `sorting by "price" is not allowed`},
+		{name: "sort on hidden field with role", claims: admin, query: "sort=-price"},
+		{name: "default sort", claims: member, query: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api := &crudApi[testWidget]{
+				model:       parseModel[testWidget](),
+				sortable:    []string{"name", "count", "price"},
+				readRoles:   map[string][]string{"price": {"admin"}},
+				maxPageSize: DefaultMaxPageSize,
+			}
+
+			ctx := newRequestCtx(t, "/widgets?"+test.query)
+
+			if test.claims != nil {
+				ctx.SetUserContext(auth.WithClaims(context.Background(), test.claims))
+			}
+
+			_, err := api.parseListQuery(ctx)
+
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", test.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPresentHidesFieldsByRoleOrScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		claims     *auth.Claims
+		wantHidden bool
+	}{
+		{name: "anonymous", wantHidden: true},
+		{name: "member", claims: &auth.Claims{Subject: "u1", Raw: map[string]any{}}, wantHidden: true},
+		{name: "unrelated scope", claims: &auth.Claims{Subject: "apikey:1", Raw: map[string]any{"scopes": []any{"read"}}}, wantHidden: true},
+		{name: "admin role", claims: &auth.Claims{Subject: "u2", Raw: map[string]any{"roles": []any{"admin"}}}},
+		{name: "admin scope only", claims: &auth.Claims{Subject: "apikey:2", Raw: map[string]any{"scopes": []any{"admin"}}}},
+		{name: "admin in space separated scope", claims: &auth.Claims{Subject: "apikey:3", Raw: map[string]any{"scope": "read admin"}}},
+		{name: "owner", claims: &auth.Claims{Subject: "owner", Raw: map[string]any{}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api := &crudApi[testWidget]{
+				model:     parseModel[testWidget](),
+				readRoles: map[string][]string{"price": {"admin", OwnerRole}},
+				owner: func(claims *auth.Claims, entity *testWidget) bool {
+					return claims.Subject == entity.Name
+				},
+			}
+
+			ctx := context.Background()
+
+			if test.claims != nil {
+				ctx = auth.WithClaims(ctx, test.claims)
+			}
+
+			item, redacted, err := api.present(ctx, &testWidget{Name: "owner", Price: 12.5}, nil)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, visible := item.(map[string]any)["price"]
+
+			if redacted != test.wantHidden || visible == test.wantHidden {
+				t.Fatalf("expected hidden %v, got redacted %v visible %v", test.wantHidden, redacted, visible)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package crud
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+type WriteMode int
+
+const (
+	Lenient WriteMode = iota
+	Strict
+)
+
+func (c *crudApi[T]) guardWritableFields(schema *openapi3.Schema, payload map[string]any) error {
+	if schema == nil {
+		return nil
+	}
+
+	rejected := []string{}
+
+	for key := range payload {
+		if _, writable := schema.Properties[key]; writable {
+			continue
+		}
+
+		if c.writeMode == Lenient {
+			delete(payload, key)
+
+			continue
+		}
+
+		if _, exists := c.entity.Properties[key]; exists {
+			rejected = append(rejected, fmt.Sprintf("%s (read-only)", key))
+		} else {
+			rejected = append(rejected, fmt.Sprintf("%s (unknown)", key))
+		}
+	}
+
+	if len(rejected) == 0 {
+		return nil
+	}
+
+	slices.Sort(rejected)
+
+	return fmt.Errorf("the following fields cannot be written: %s", strings.Join(rejected, ", "))
+}
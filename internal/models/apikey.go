@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type ApiKey struct {
+	SoftDeleteBase
+	Name          string     `json:"name" gorm:"type:text;not null;" validate:"required,gte=3"`
+	Prefix        string     `json:"prefix" gorm:"type:text;not null;" crud:"readonly"`
+	Hash          string     `json:"-" gorm:"type:text;uniqueIndex;not null;"`
+	Scopes        []string   `json:"scopes" gorm:"type:jsonb;serializer:json;not null;default:'[]';"`
+	ExpiresAt     *time.Time `json:"expiresAt"`
+	LastUsedAt    *time.Time `json:"lastUsedAt" crud:"readonly"`
+	OwnerTenantId *uuid.UUID `json:"ownerTenantId" gorm:"type:uuid;index"`
+	Key           string     `json:"key,omitempty" gorm:"-" crud:"readonly"`
+}
+
+func (a *ApiKey) Validate() error {
+	validate := validator.New()
+
+	return validate.Struct(a)
+}
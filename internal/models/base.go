@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Base struct {
@@ -11,3 +12,12 @@ type Base struct {
 	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
 	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
 }
+
+type SoftDeleteBase struct {
+	Base
+	DeletedAt gorm.DeletedAt `json:"deletedAt" gorm:"index" crud:"readonly"`
+}
+
+type TenantBase struct {
+	TenantId uuid.UUID `json:"tenantId" gorm:"type:uuid;not null;index" crud:"readonly"`
+}
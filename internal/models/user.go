@@ -5,9 +5,10 @@ import (
 )
 
 type User struct {
-	Base
-	Name  string `json:"name" gorm:"type:text;not null;" validate:"gte=3;required"`
-	Email string `json:"email" gorm:"type:text;unique;not null;" validate:"email;required"`
+	SoftDeleteBase
+	Name    string `json:"name" gorm:"type:text;not null;" validate:"required,gte=3"`
+	Email   string `json:"email" gorm:"type:text;unique;not null;" validate:"required,email"`
+	Version int64  `json:"version" gorm:"not null;default:1;" crud:"version"`
 }
 
 func (u *User) Validate() error {
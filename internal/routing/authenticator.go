@@ -0,0 +1,11 @@
+package routing
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Authenticator interface {
+	Authenticate(ctx *fiber.Ctx) (bool, error)
+	SecurityScheme() (string, *openapi3.SecurityScheme)
+}
@@ -12,6 +12,7 @@ type OpenAPIMetadata struct {
 	Parameters  []*openapi3.ParameterRef
 	RequestBody *openapi3.RequestBodyRef
 	Responses   *openapi3.Responses
+	Security    *openapi3.SecurityRequirements
 }
 
 type RouteMethod string
@@ -27,10 +28,9 @@ const (
 type Route struct {
 	OpenAPIMetadata
 
-	Entity string
-
-	CreateSchema *openapi3.Schema
-	UpdateSchema *openapi3.Schema
+	Entity          string
+	Schemas         openapi3.Schemas
+	SecuritySchemes openapi3.SecuritySchemes
 
 	Method      RouteMethod
 	Path        string
@@ -0,0 +1,75 @@
+package schemas
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+func NewBulkSchema(item *openapi3.SchemaRef, maxItems int) *openapi3.Schema {
+	items := openapi3.NewArraySchema().
+		WithMinItems(1).
+		WithMaxItems(int64(maxItems))
+	items.Items = item
+
+	return openapi3.NewObjectSchema().
+		WithProperties(map[string]*openapi3.Schema{
+			"items": items,
+		}).
+		WithRequired([]string{
+			"items",
+		})
+}
+
+func NewBulkIdsSchema(maxItems int) *openapi3.Schema {
+	return openapi3.NewObjectSchema().
+		WithProperties(map[string]*openapi3.Schema{
+			"ids": openapi3.NewArraySchema().
+				WithItems(openapi3.NewUUIDSchema()).
+				WithMinItems(1).
+				WithMaxItems(int64(maxItems)),
+		}).
+		WithRequired([]string{
+			"ids",
+		})
+}
+
+func NewBulkPatchItemSchema(patch *openapi3.Schema) *openapi3.Schema {
+	item := *patch
+	item.Properties = openapi3.Schemas{}
+
+	for name, property := range patch.Properties {
+		item.Properties[name] = property
+	}
+
+	item.Properties["id"] = openapi3.NewUUIDSchema().NewRef()
+	item.Required = []string{"id"}
+
+	return &item
+}
+
+func NewBulkResultSchema(entity *openapi3.SchemaRef) *openapi3.Schema {
+	result := openapi3.NewObjectSchema().
+		WithProperties(map[string]*openapi3.Schema{
+			"index":  openapi3.NewInt32Schema(),
+			"status": openapi3.NewInt32Schema(),
+		}).
+		WithPropertyRef("item", entity).
+		WithPropertyRef("error", Ref("Problem", ErrorSchema)).
+		WithRequired([]string{
+			"index",
+			"status",
+		})
+
+	return openapi3.NewObjectSchema().
+		WithProperties(map[string]*openapi3.Schema{
+			"results": openapi3.NewArraySchema().WithItems(result),
+		}).
+		WithRequired([]string{
+			"results",
+		})
+}
+
+var BulkDeleteSchema = openapi3.NewObjectSchema().
+	WithProperties(map[string]*openapi3.Schema{
+		"deleted": openapi3.NewInt64Schema(),
+	}).
+	WithRequired([]string{
+		"deleted",
+	})
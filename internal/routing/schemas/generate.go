@@ -0,0 +1,406 @@
+package schemas
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/uuid"
+	"gorm.io/gorm/schema"
+)
+
+type schemaMode int
+
+const (
+	entityMode schemaMode = iota
+	createMode
+	updateMode
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+var uuidType = reflect.TypeOf(uuid.UUID{})
+
+func NewEntitySchema(modelType reflect.Type) *openapi3.Schema {
+	return generateObject(modelType, entityMode, map[reflect.Type]bool{})
+}
+
+func NewCreateSchema(modelType reflect.Type) *openapi3.Schema {
+	return generateObject(modelType, createMode, map[reflect.Type]bool{})
+}
+
+func NewUpdateSchema(modelType reflect.Type) *openapi3.Schema {
+	return generateObject(modelType, updateMode, map[reflect.Type]bool{})
+}
+
+func IsVersion(field reflect.StructField) bool {
+	for _, option := range strings.Split(field.Tag.Get("crud"), ",") {
+		if strings.TrimSpace(option) == "version" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ReadRoles(field reflect.StructField) []string {
+	return tagRoles(field, "read")
+}
+
+func WriteRoles(field reflect.StructField) []string {
+	return tagRoles(field, "write")
+}
+
+func tagRoles(field reflect.StructField, name string) []string {
+	for _, option := range strings.Split(field.Tag.Get("crud"), ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(option), "=")
+
+		if !found || key != name {
+			continue
+		}
+
+		roles := []string{}
+
+		for _, role := range strings.Split(value, "|") {
+			if role = strings.TrimSpace(role); role != "" {
+				roles = append(roles, role)
+			}
+		}
+
+		return roles
+	}
+
+	return nil
+}
+
+func IsReadOnly(field reflect.StructField) bool {
+	for _, option := range strings.Split(field.Tag.Get("crud"), ",") {
+		if option = strings.TrimSpace(option); option == "readonly" || option == "version" {
+			return true
+		}
+	}
+
+	settings := schema.ParseTagSetting(field.Tag.Get("gorm"), ";")
+
+	for _, key := range []string{"PRIMARYKEY", "PRIMARY_KEY", "AUTOCREATETIME", "AUTOUPDATETIME"} {
+		if _, exists := settings[key]; exists {
+			return true
+		}
+	}
+
+	if permission, exists := settings["-"]; exists && permission != "migration" {
+		return true
+	}
+
+	if permission, exists := settings["<-"]; exists && permission == "false" {
+		return true
+	}
+
+	if permission, exists := settings["->"]; exists && permission != "false" {
+		if _, writable := settings["<-"]; !writable {
+			return true
+		}
+	}
+
+	return false
+}
+
+func JsonName(field reflect.StructField) (string, bool) {
+	tag, exists := field.Tag.Lookup("json")
+
+	if !exists {
+		return field.Name, false
+	}
+
+	parts := strings.Split(tag, ",")
+
+	if parts[0] == "-" && len(parts) == 1 {
+		return "", false
+	}
+
+	name := parts[0]
+
+	if name == "" {
+		name = field.Name
+	}
+
+	omitEmpty := false
+
+	for _, option := range parts[1:] {
+		if option == "omitempty" || option == "omitzero" {
+			omitEmpty = true
+		}
+	}
+
+	return name, omitEmpty
+}
+
+func generateObject(modelType reflect.Type, mode schemaMode, visited map[reflect.Type]bool) *openapi3.Schema {
+	for modelType.Kind() == reflect.Pointer {
+		modelType = modelType.Elem()
+	}
+
+	object := openapi3.NewObjectSchema()
+	required := []string{}
+
+	visited[modelType] = true
+	defer delete(visited, modelType)
+
+	for index := 0; index < modelType.NumField(); index++ {
+		field := modelType.Field(index)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := generateObject(field.Type, mode, visited)
+
+			for name, property := range embedded.Properties {
+				object.Properties[name] = property
+			}
+
+			required = append(required, embedded.Required...)
+
+			continue
+		}
+
+		name, omitEmpty := JsonName(field)
+
+		if name == "" {
+			continue
+		}
+
+		readOnly := IsReadOnly(field)
+		relation := isRelation(field.Type)
+
+		if mode != entityMode && (readOnly || relation) {
+			continue
+		}
+
+		if relation && visited[indirect(elementType(field.Type))] {
+			continue
+		}
+
+		property := generateField(field, mode, visited)
+
+		if mode == entityMode && (readOnly || relation) {
+			property.ReadOnly = true
+		}
+
+		object.Properties[name] = openapi3.NewSchemaRef("", property)
+
+		if !relation && isRequired(field, mode, omitEmpty) {
+			required = append(required, name)
+		}
+	}
+
+	return object.WithRequired(required)
+}
+
+func generateField(field reflect.StructField, mode schemaMode, visited map[reflect.Type]bool) *openapi3.Schema {
+	settings := schema.ParseTagSetting(field.Tag.Get("gorm"), ";")
+
+	property := generateType(field.Type, mode, visited)
+
+	if columnType, exists := settings["TYPE"]; exists && strings.EqualFold(columnType, "uuid") {
+		property.Format = "uuid"
+	}
+
+	if size, exists := settings["SIZE"]; exists && property.Type.Is(openapi3.TypeString) {
+		if value, err := strconv.ParseUint(size, 10, 64); err == nil {
+			property.MaxLength = &value
+		}
+	}
+
+	if _, exists := settings["UNIQUE"]; exists {
+		property.Description = "Must be unique."
+	}
+
+	applyValidation(property, field.Tag.Get("validate"))
+
+	return property
+}
+
+func generateType(fieldType reflect.Type, mode schemaMode, visited map[reflect.Type]bool) *openapi3.Schema {
+	if fieldType.Kind() == reflect.Pointer {
+		return generateType(fieldType.Elem(), mode, visited).WithNullable()
+	}
+
+	switch {
+	case fieldType == timeType:
+		return openapi3.NewDateTimeSchema()
+	case fieldType == uuidType:
+		return openapi3.NewUUIDSchema()
+	case isNullable(fieldType):
+		return generateType(fieldType.Field(0).Type, mode, visited).WithNullable()
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String:
+		return openapi3.NewStringSchema()
+	case reflect.Bool:
+		return openapi3.NewBoolSchema()
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return openapi3.NewInt32Schema()
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return openapi3.NewInt64Schema()
+	case reflect.Float32, reflect.Float64:
+		return openapi3.NewFloat64Schema()
+	case reflect.Slice, reflect.Array:
+		if fieldType.Elem().Kind() == reflect.Uint8 {
+			return openapi3.NewBytesSchema()
+		}
+
+		return openapi3.NewArraySchema().WithItems(generateType(fieldType.Elem(), mode, visited))
+	case reflect.Map:
+		return openapi3.NewObjectSchema().WithAdditionalProperties(generateType(fieldType.Elem(), mode, visited))
+	case reflect.Struct:
+		return generateObject(fieldType, mode, visited)
+	}
+
+	return openapi3.NewSchema()
+}
+
+func applyValidation(property *openapi3.Schema, tag string) {
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		switch name {
+		case "email":
+			property.Format = "email"
+		case "url", "uri":
+			property.Format = "uri"
+		case "uuid", "uuid4":
+			property.Format = "uuid"
+		case "datetime":
+			property.Format = "date-time"
+		case "ip":
+			property.Format = "ipv4"
+		case "oneof":
+			values := []any{}
+
+			for _, value := range strings.Fields(param) {
+				values = append(values, value)
+			}
+
+			property.Enum = values
+		case "len":
+			applyBound(property, param, true, false)
+			applyBound(property, param, false, false)
+		case "min", "gte":
+			applyBound(property, param, true, false)
+		case "max", "lte":
+			applyBound(property, param, false, false)
+		case "gt":
+			applyBound(property, param, true, true)
+		case "lt":
+			applyBound(property, param, false, true)
+		}
+	}
+}
+
+func applyBound(property *openapi3.Schema, param string, lower bool, exclusive bool) {
+	value, err := strconv.ParseFloat(param, 64)
+
+	if err != nil {
+		return
+	}
+
+	switch {
+	case property.Type.Is(openapi3.TypeString):
+		length := uint64(value)
+
+		if exclusive && lower {
+			length++
+		} else if exclusive && length > 0 {
+			length--
+		}
+
+		if lower {
+			property.MinLength = length
+		} else {
+			property.MaxLength = &length
+		}
+	case property.Type.Is(openapi3.TypeArray):
+		count := uint64(value)
+
+		if lower {
+			property.MinItems = count
+		} else {
+			property.MaxItems = &count
+		}
+	case property.Type.Is(openapi3.TypeInteger), property.Type.Is(openapi3.TypeNumber):
+		if lower {
+			property.Min = &value
+			property.ExclusiveMin = exclusive
+		} else {
+			property.Max = &value
+			property.ExclusiveMax = exclusive
+		}
+	}
+}
+
+func isRequired(field reflect.StructField, mode schemaMode, omitEmpty bool) bool {
+	if mode == entityMode {
+		return !omitEmpty && field.Type.Kind() != reflect.Pointer
+	}
+
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		if strings.TrimSpace(rule) == "required" {
+			return true
+		}
+	}
+
+	settings := schema.ParseTagSetting(field.Tag.Get("gorm"), ";")
+
+	_, notNull := settings["NOT NULL"]
+	_, hasDefault := settings["DEFAULT"]
+
+	return notNull && !hasDefault && field.Type.Kind() != reflect.Pointer
+}
+
+func isNullable(fieldType reflect.Type) bool {
+	if fieldType.Kind() != reflect.Struct || fieldType.NumField() != 2 {
+		return false
+	}
+
+	valid := fieldType.Field(1)
+
+	return valid.Name == "Valid" && valid.Type.Kind() == reflect.Bool
+}
+
+func isRelation(fieldType reflect.Type) bool {
+	fieldType = indirect(elementType(fieldType))
+
+	if fieldType.Kind() != reflect.Struct || fieldType == timeType {
+		return false
+	}
+
+	for index := 0; index < fieldType.NumField(); index++ {
+		if fieldType.Field(index).Anonymous || fieldType.Field(index).Tag.Get("gorm") != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func elementType(fieldType reflect.Type) reflect.Type {
+	fieldType = indirect(fieldType)
+
+	if fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array {
+		return fieldType.Elem()
+	}
+
+	return fieldType
+}
+
+func indirect(fieldType reflect.Type) reflect.Type {
+	for fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+
+	return fieldType
+}
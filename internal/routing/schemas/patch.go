@@ -0,0 +1,23 @@
+package schemas
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+var JSONPatchSchema = openapi3.NewArraySchema().
+	WithItems(openapi3.NewObjectSchema().
+		WithProperties(map[string]*openapi3.Schema{
+			"op":    openapi3.NewStringSchema().WithEnum("add", "remove", "replace", "move", "copy", "test"),
+			"path":  openapi3.NewStringSchema().WithFormat("json-pointer"),
+			"from":  openapi3.NewStringSchema().WithFormat("json-pointer"),
+			"value": openapi3.NewSchema(),
+		}).
+		WithRequired([]string{
+			"op",
+			"path",
+		}))
+
+func NewPatchSchema(update *openapi3.Schema) *openapi3.Schema {
+	patch := *update
+	patch.Required = nil
+
+	return &patch
+}
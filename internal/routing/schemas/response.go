@@ -1,28 +1,110 @@
 package schemas
 
 import (
+	"fmt"
+
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
-var ErrorSchema = openapi3.NewSchema().
+const ProblemContentType = "application/problem+json"
+
+var fieldErrorSchema = openapi3.NewObjectSchema().
 	WithProperties(map[string]*openapi3.Schema{
-		"error":   openapi3.NewStringSchema().WithFormat("text"),
+		"path":    openapi3.NewStringSchema().WithFormat("json-pointer"),
+		"rule":    openapi3.NewStringSchema(),
 		"message": openapi3.NewStringSchema().WithFormat("text"),
 	}).
 	WithRequired([]string{
-		"error",
+		"path",
+		"rule",
 		"message",
 	})
 
-var SuccessSchema = openapi3.NewSchema().
-	WithProperties(map[string]*openapi3.Schema{
-		"item": openapi3.NewAnyOfSchema(
-			UserSchema,
-		),
-		"items": openapi3.NewArraySchema().WithItems(
-			openapi3.NewAnyOfSchema(
-				UserSchema,
-			),
-		),
-	}).
-	WithRequired([]string{})
+var problemProperties = map[string]*openapi3.Schema{
+	"type":     openapi3.NewStringSchema().WithFormat("uri-reference"),
+	"title":    openapi3.NewStringSchema().WithFormat("text"),
+	"status":   openapi3.NewInt32Schema(),
+	"detail":   openapi3.NewStringSchema().WithFormat("text"),
+	"instance": openapi3.NewStringSchema().WithFormat("uri-reference"),
+	"errors":   openapi3.NewArraySchema().WithItems(fieldErrorSchema),
+}
+
+var ErrorSchema = openapi3.NewObjectSchema().
+	WithProperties(problemProperties).
+	WithRequired([]string{
+		"type",
+		"title",
+		"status",
+	})
+
+var ValidationErrorSchema = openapi3.NewObjectSchema().
+	WithProperties(problemProperties).
+	WithRequired([]string{
+		"type",
+		"title",
+		"status",
+		"errors",
+	})
+
+func NewErrorResponse(description string) *openapi3.ResponseRef {
+	return &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription(description).
+			WithContent(openapi3.Content{
+				ProblemContentType: openapi3.NewMediaType().
+					WithSchemaRef(Ref("Problem", ErrorSchema)),
+			}),
+	}
+}
+
+func NewValidationErrorResponse(description string) *openapi3.ResponseRef {
+	return &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription(description).
+			WithContent(openapi3.Content{
+				ProblemContentType: openapi3.NewMediaType().
+					WithSchemaRef(Ref("ValidationProblem", ValidationErrorSchema)),
+			}),
+	}
+}
+
+func Ref(name string, schema *openapi3.Schema) *openapi3.SchemaRef {
+	return openapi3.NewSchemaRef(fmt.Sprintf("#/components/schemas/%s", name), schema)
+}
+
+func NewItemSchema(entity *openapi3.SchemaRef) *openapi3.Schema {
+	return openapi3.NewObjectSchema().
+		WithPropertyRef("item", entity).
+		WithRequired([]string{
+			"item",
+		})
+}
+
+func NewListSchema(entity *openapi3.SchemaRef) *openapi3.Schema {
+	items := openapi3.NewArraySchema()
+	items.Items = entity
+
+	return openapi3.NewObjectSchema().
+		WithProperties(map[string]*openapi3.Schema{
+			"items":      items,
+			"total":      openapi3.NewInt64Schema(),
+			"nextCursor": openapi3.NewStringSchema().WithNullable(),
+			"hasMore":    openapi3.NewBoolSchema(),
+		}).
+		WithRequired([]string{
+			"items",
+			"total",
+			"nextCursor",
+			"hasMore",
+		})
+}
+
+func NewUpsertSchema(entity *openapi3.SchemaRef) *openapi3.Schema {
+	return openapi3.NewObjectSchema().
+		WithPropertyRef("item", entity).
+		WithProperty("operation", openapi3.NewStringSchema().WithEnum("inserted", "updated")).
+		WithRequired([]string{
+			"item",
+			"operation",
+		})
+}
@@ -37,5 +37,6 @@ func (s *storage) Database() *gorm.DB {
 func (s *storage) Migrate() error {
 	return s.db.AutoMigrate(
 		&models.User{},
+		&models.ApiKey{},
 	)
 }